@@ -6,10 +6,10 @@ package flaggy
 // returned when setting the key, such as failures to convert the string
 // into the appropriate flag value.  We stop assigning values as soon
 // as we find a any parser that accepts it.
-func setValueForParsers(key string, value string, parsers ...ArgumentParser) (bool, error) {
+func setValueForParsers(key string, value string, source ValueSource, parsers ...*Subcommand) (bool, error) {
 
 	for _, p := range parsers {
-		valueWasSet, err := p.SetValueForKey(key, value)
+		valueWasSet, err := p.setValueForKey(key, value, source)
 		if err != nil {
 			return valueWasSet, err
 		}
@@ -0,0 +1,209 @@
+package flaggy
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// bindTag holds the options parsed from a flaggy struct tag.
+type bindTag struct {
+	Short    string
+	Long     string
+	Desc     string
+	Env      string
+	Position int
+	Required bool
+	Hidden   bool
+}
+
+// BindStruct adds a flag for every field of the struct pointed to by ptr that
+// has a flaggy tag.  Tags are written like:
+//
+//	Port int `flaggy:"short=p,long=port,desc=Port to listen on,required,env=PORT,hidden"`
+//
+// When neither short nor long is set, the long name is the field name in
+// kebab case.  Fields with a positional tag, like `positional:"1"`, are added
+// as positional values instead, named by their long name.  Fields of struct
+// types that are not supported as flag values become subcommands named by
+// their long name, or the field name in kebab case when only short is set,
+// at the position set with position=N (1 by default), and their own fields
+// are bound to that subcommand.  Untagged embedded structs
+// are bound to the same subcommand.  Fields tagged with flaggy:"-" are skipped.
+//
+// Any type supported by the typed flag adders can be bound, along with custom
+// values.  Duplicate flag names panic as they do when flags are added one by
+// one.  Fields that can not be bound are returned as an error.
+func (sc *Subcommand) BindStruct(ptr interface{}) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return errors.New("BindStruct requires a pointer to a struct, but got " + fmt.Sprintf("%T", ptr))
+	}
+	return sc.bindStructValue(v.Elem())
+}
+
+// bindStructValue binds the fields of an addressable struct value.  Embedded
+// structs are walked by value so that the exported fields of unexported
+// embedded types can still be bound.
+func (sc *Subcommand) bindStructValue(v reflect.Value) error {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tagValue, hasTag := field.Tag.Lookup("flaggy")
+		positionalValue, isPositional := field.Tag.Lookup("positional")
+		if tagValue == "-" {
+			continue
+		}
+
+		// embedded structs share the fields of this subcommand
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && !hasTag && !isPositional {
+			if err := sc.bindStructValue(v.Field(i)); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		fieldPtr := v.Field(i).Addr().Interface()
+		_, supported, _ := valueAsString(fieldPtr)
+
+		if !hasTag && !isPositional {
+			continue
+		}
+
+		tag, err := parseBindTag(tagValue)
+		if err != nil {
+			return errors.New("unable to bind field " + field.Name + ": " + err.Error())
+		}
+		if tag.Short == "" && tag.Long == "" {
+			tag.Long = kebabCase(field.Name)
+		}
+
+		switch {
+		case isPositional:
+			position, err := strconv.Atoi(positionalValue)
+			if err != nil || position < 1 {
+				return errors.New("unable to bind field " + field.Name + ": invalid position " + strconv.Quote(positionalValue))
+			}
+			if !supported {
+				return errors.New("unable to bind field " + field.Name + ": type " + field.Type.String() + " is not supported")
+			}
+			name := tag.Long
+			if name == "" {
+				name = tag.Short
+			}
+			sc.AddPositionalValue(fieldPtr, name, position, tag.Required, tag.Desc)
+			sc.PositionalFlags[len(sc.PositionalFlags)-1].Hidden = tag.Hidden
+		case supported:
+			f := sc.add(fieldPtr, tag.Short, tag.Long, tag.Desc)
+			f.Required = tag.Required
+			f.EnvVar = tag.Env
+			f.Hidden = tag.Hidden
+		case field.Type.Kind() == reflect.Struct:
+			// subcommands need a name, even when only a short name was given
+			if tag.Long == "" {
+				tag.Long = kebabCase(field.Name)
+			}
+			sub := NewSubcommand(tag.Long)
+			sub.ShortName = tag.Short
+			sub.Description = tag.Desc
+			sub.Hidden = tag.Hidden
+			if err := sub.BindStruct(fieldPtr); err != nil {
+				return err
+			}
+			position := tag.Position
+			if position == 0 {
+				position = 1
+			}
+			sc.AttachSubcommand(sub, position)
+		default:
+			return errors.New("unable to bind field " + field.Name + ": type " + field.Type.String() + " is not supported")
+		}
+	}
+	return nil
+}
+
+// BindStruct adds flags, positional values, and subcommands to the default
+// parser from the fields of the struct pointed to by ptr.
+func BindStruct(ptr interface{}) error {
+	return DefaultParser.BindStruct(ptr)
+}
+
+// bindTagOptions lists the option names understood in flaggy struct tags.
+var bindTagOptions = []string{"short=", "long=", "desc=", "env=", "position=", "required", "hidden"}
+
+// isBindTagOption reports whether the tag segment starts a new option.
+func isBindTagOption(segment string) bool {
+	for _, option := range bindTagOptions {
+		if segment == option || strings.HasPrefix(segment, option) && strings.HasSuffix(option, "=") {
+			return true
+		}
+	}
+	return false
+}
+
+// parseBindTag parses the comma separated options of a flaggy struct tag.
+// Descriptions may contain commas, as segments that do not start a known
+// option are added back onto the description.
+func parseBindTag(tag string) (bindTag, error) {
+	var parsed bindTag
+	if tag == "" {
+		return parsed, nil
+	}
+	segments := strings.Split(tag, ",")
+	for i := 0; i < len(segments); i++ {
+		segment := strings.TrimSpace(segments[i])
+		key, value, _ := strings.Cut(segment, "=")
+		switch key {
+		case "short":
+			parsed.Short = value
+		case "long":
+			parsed.Long = value
+		case "env":
+			parsed.Env = value
+		case "required":
+			parsed.Required = true
+		case "hidden":
+			parsed.Hidden = true
+		case "position":
+			position, err := strconv.Atoi(value)
+			if err != nil || position < 1 {
+				return parsed, errors.New("invalid position " + strconv.Quote(value))
+			}
+			parsed.Position = position
+		case "desc":
+			desc := value
+			for i+1 < len(segments) && !isBindTagOption(strings.TrimSpace(segments[i+1])) {
+				i++
+				desc += "," + segments[i]
+			}
+			parsed.Desc = desc
+		default:
+			return parsed, errors.New("unknown tag option " + strconv.Quote(segment))
+		}
+	}
+	return parsed, nil
+}
+
+// kebabCase converts a Go field name like MaxWait or TLSCert into a flag name
+// like max-wait or tls-cert.
+func kebabCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) && i > 0 {
+			prevLower := unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1])
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if prevLower || (unicode.IsUpper(runes[i-1]) && nextLower) {
+				b.WriteRune('-')
+			}
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
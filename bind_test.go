@@ -0,0 +1,135 @@
+package flaggy_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/integrii/flaggy"
+)
+
+// commonOptions is embedded into serverConfig to check that embedded
+// structs share the parent's flags.
+type commonOptions struct {
+	Verbose bool `flaggy:"short=v,desc=Verbose output"`
+}
+
+// deployConfig is bound as the deploy subcommand.
+type deployConfig struct {
+	Target  string        `positional:"1" flaggy:"long=target,required,desc=Where to deploy"`
+	MaxWait time.Duration `flaggy:""`
+	Replica int           `flaggy:"short=r,long=replicas,env=TEST_BIND_REPLICAS"`
+}
+
+// serverConfig is the root struct bound by the BindStruct tests.
+type serverConfig struct {
+	commonOptions
+	Port     int      `flaggy:"short=p,long=port,desc=Port to listen on, defaults to 8080,required"`
+	Hosts    []string `flaggy:"long=host,desc=Hosts to serve"`
+	Secret   string   `flaggy:"long=secret,hidden"`
+	Ignored  string   `flaggy:"-"`
+	Untagged string
+	Deploy   deployConfig `flaggy:"long=deploy,short=d,desc=Deploy the server"`
+}
+
+func TestBindStruct(t *testing.T) {
+	t.Setenv("TEST_BIND_REPLICAS", "4")
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	cfg := serverConfig{Port: 8080}
+	if err := p.BindStruct(&cfg); err != nil {
+		t.Fatalf("bind error: %v", err)
+	}
+	args := []string{"-v", "--port", "9000", "--host", "a", "--host", "b", "d", "prod", "--max-wait", "5s"}
+	if err := p.ParseArgs(args); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if !cfg.Verbose || cfg.Port != 9000 || strings.Join(cfg.Hosts, ",") != "a,b" {
+		t.Fatalf("unexpected root values: %+v", cfg)
+	}
+	if cfg.Deploy.Target != "prod" || cfg.Deploy.MaxWait != 5*time.Second || cfg.Deploy.Replica != 4 {
+		t.Fatalf("unexpected deploy values: %+v", cfg.Deploy)
+	}
+	if len(p.Flags) != 4 {
+		t.Fatalf("expected only tagged and embedded fields to be bound, got %d flags", len(p.Flags))
+	}
+}
+
+func TestBindStructTagOptions(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var cfg serverConfig
+	if err := p.BindStruct(&cfg); err != nil {
+		t.Fatalf("bind error: %v", err)
+	}
+	for _, f := range p.Flags {
+		switch f.LongName {
+		case "port":
+			if !f.Required || f.ShortName != "p" || f.Description != "Port to listen on, defaults to 8080" {
+				t.Fatalf("unexpected port flag: %+v", f)
+			}
+		case "secret":
+			if !f.Hidden {
+				t.Fatal("expected secret flag to be hidden")
+			}
+		}
+	}
+	err := p.ParseArgs([]string{})
+	if err == nil || !strings.Contains(err.Error(), "--port") {
+		t.Fatalf("expected required port flag to be enforced, got %v", err)
+	}
+}
+
+func TestBindStructErrors(t *testing.T) {
+	var notPointer serverConfig
+	if err := flaggy.NewParser("app").BindStruct(notPointer); err == nil {
+		t.Fatal("expected error binding a non-pointer")
+	}
+
+	var unsupported struct {
+		Channel chan int `flaggy:"long=channel"`
+	}
+	if err := flaggy.NewParser("app").BindStruct(&unsupported); err == nil {
+		t.Fatal("expected error binding an unsupported type")
+	}
+
+	var badTag struct {
+		Name string `flaggy:"lnog=name"`
+	}
+	if err := flaggy.NewParser("app").BindStruct(&badTag); err == nil {
+		t.Fatal("expected error for an unknown tag option")
+	}
+}
+
+func TestBindStructSubcommandShortNameOnly(t *testing.T) {
+	var cfg struct {
+		DeployTarget deployConfig `flaggy:"short=d,desc=deploy"`
+	}
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	if err := p.BindStruct(&cfg); err != nil {
+		t.Fatalf("bind error: %v", err)
+	}
+	if len(p.Subcommands) != 1 || p.Subcommands[0].Name != "deploy-target" || p.Subcommands[0].ShortName != "d" {
+		t.Fatalf("expected the subcommand to be named after the field: %+v", p.Subcommands)
+	}
+	if err := p.ParseArgs([]string{"d", "prod"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if cfg.DeployTarget.Target != "prod" {
+		t.Fatalf("unexpected deploy values: %+v", cfg.DeployTarget)
+	}
+}
+
+func TestBindStructDuplicateNamePanics(t *testing.T) {
+	var dup struct {
+		A string `flaggy:"long=name"`
+		B string `flaggy:"long=name"`
+	}
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected duplicate flag names to panic")
+		}
+	}()
+	flaggy.NewParser("app").BindStruct(&dup)
+}
@@ -0,0 +1,259 @@
+package flaggy
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// completeCommand is the hidden command that the generated completion
+// scripts run to ask the program for candidates.  The arguments after it
+// are the words on the command line, with the word being completed last.
+const completeCommand = "__complete"
+
+// Candidate is a value offered by shell completion.
+type Candidate struct {
+	Value       string // the text placed on the command line
+	Description string // shown beside the value by shells that support it
+}
+
+// CompletionFunc returns the completion candidates for a flag or positional
+// value.  prefix is the partial word being completed and ctx is the
+// subcommand that the value belongs to.
+type CompletionFunc func(prefix string, ctx *Subcommand) []Candidate
+
+// completionState describes a partial command line as the parser would see
+// it, up to the word being completed.
+type completionState struct {
+	path    []*Subcommand // the root parser followed by every subcommand used
+	depth   int           // the positional values supplied to the last subcommand
+	pending *Flag         // a flag still waiting for its value
+	used    []*Flag       // flags already on the command line
+	final   bool          // indicates a -- was seen, ending flags and subcommands
+}
+
+// subcommand returns the most specific subcommand on the command line.
+func (s *completionState) subcommand() *Subcommand {
+	return s.path[len(s.path)-1]
+}
+
+// lookupFlag finds a flag on the current subcommand or the root parser, the
+// same places the parser looks when parsing.
+func (s *completionState) lookupFlag(name string) *Flag {
+	for _, sc := range []*Subcommand{s.subcommand(), s.path[0]} {
+		for _, f := range sc.Flags {
+			if f.HasName(name) {
+				return f
+			}
+		}
+	}
+	return nil
+}
+
+// completionState walks the words before the one being completed.
+// Subcommands are matched at their positions and the values of flags that
+// take one are skipped, like parsing does.
+func (p *Parser) completionState(args []string) completionState {
+	if p.POSIXShortFlags {
+		args = p.expandPOSIXShortFlags(args)
+	} else {
+		args = p.expandCounterClusters(args)
+	}
+
+	state := completionState{path: []*Subcommand{&p.Subcommand}}
+	for i := 0; i < len(args); i++ {
+		a := args[i]
+		if state.pending != nil {
+			state.pending = nil
+			continue
+		}
+		switch determineArgType(a) {
+		case argIsFinal:
+			state.final = true
+			return state
+		case argIsFlagWithValue:
+			key, _ := parseArgWithValue(parseFlagToName(a))
+			if f := state.lookupFlag(key); f != nil {
+				state.used = append(state.used, f)
+			}
+		case argIsFlagWithSpace:
+			name := parseFlagToName(a)
+			if p.isBuiltinFlag(name) {
+				continue
+			}
+			f := state.lookupFlag(name)
+			if f == nil {
+				// flags of a subcommand used before its name keep their
+				// value, just like they are forwarded during parsing
+				if i+1 < len(args) && shouldReserveNextArgForChild(state.subcommand(), state.depth, args[i+1]) {
+					i++
+				}
+				continue
+			}
+			state.used = append(state.used, f)
+			if !f.isBool() {
+				state.pending = f
+			}
+		default:
+			state.depth++
+			if cmd := state.subcommand().matchSubcommand(a, state.depth); cmd != nil {
+				state.path = append(state.path, cmd)
+				state.depth = 0
+			}
+		}
+	}
+	return state
+}
+
+// isBuiltinFlag reports whether name is the help or version flag, which
+// never take a value.
+func (p *Parser) isBuiltinFlag(name string) bool {
+	if p.ShowHelpWithHFlag && (name == helpFlagShortName || name == helpFlagLongName) {
+		return true
+	}
+	return p.ShowVersionWithVersionFlag && name == versionFlagLongName
+}
+
+// Complete returns the completion candidates for cursorWord, the partial
+// word being typed, when it follows args on the command line.  args does
+// not include the program name.  Subcommands are matched at their positions
+// and flag values are skipped by the same rules used when parsing, so the
+// candidates are the ones the shell completion scripts would offer.  The
+// parser is not changed and may be completed any number of times.
+func (p *Parser) Complete(args []string, cursorWord string) []Candidate {
+	state := p.completionState(args)
+	if state.final {
+		return nil
+	}
+	sc := state.subcommand()
+
+	if state.pending != nil {
+		return flagValueCandidates(state.pending, cursorWord, sc)
+	}
+
+	if strings.HasPrefix(cursorWord, "-") {
+		name, value, found := strings.Cut(cursorWord, "=")
+		if !found {
+			return state.flagCandidates(cursorWord)
+		}
+		f := state.lookupFlag(parseFlagToName(name))
+		if f == nil {
+			return nil
+		}
+		var candidates []Candidate
+		for _, c := range flagValueCandidates(f, value, sc) {
+			c.Value = name + "=" + c.Value
+			candidates = append(candidates, c)
+		}
+		return candidates
+	}
+
+	position := state.depth + 1
+	var candidates []Candidate
+	for _, cmd := range sc.Subcommands {
+		if cmd.hidden() || cmd.Position != position || !strings.HasPrefix(cmd.Name, cursorWord) {
+			continue
+		}
+		candidates = append(candidates, Candidate{Value: cmd.Name, Description: cmd.Description})
+	}
+	pos := sc.positionalForDepth(position, position)
+	if pos != nil && !pos.Hidden && pos.CompletionFunc != nil {
+		candidates = append(candidates, withPrefix(pos.CompletionFunc(cursorWord, sc), cursorWord)...)
+	}
+
+	// offer flags when nothing else is expected here
+	if len(candidates) == 0 && pos == nil && cursorWord == "" {
+		return state.flagCandidates(cursorWord)
+	}
+	return candidates
+}
+
+// flagCandidates returns the visible flags of the current subcommand and
+// the root parser that start with prefix.  Flags that share a mutually
+// exclusive group with a flag already used are left out.
+func (s *completionState) flagCandidates(prefix string) []Candidate {
+	scopes := []*Subcommand{s.subcommand()}
+	if len(s.path) > 1 {
+		scopes = append(scopes, s.path[0])
+	}
+
+	var candidates []Candidate
+	for _, sc := range scopes {
+		for _, f := range sc.Flags {
+			if f.hidden() || s.excluded(sc, f) {
+				continue
+			}
+			for _, name := range flagCompletionNames(f) {
+				if strings.HasPrefix(name, prefix) {
+					candidates = append(candidates, Candidate{Value: name, Description: f.Description})
+				}
+			}
+		}
+	}
+	return candidates
+}
+
+// excluded reports whether a flag already used shares a mutually exclusive
+// group with f.
+func (s *completionState) excluded(sc *Subcommand, f *Flag) bool {
+	for _, conflict := range sc.exclusiveWith(f) {
+		for _, used := range s.used {
+			if used == conflict {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// flagValueCandidates returns the candidates for the value of a flag from
+// its CompletionFunc, or from its choices when it is an enum.
+func flagValueCandidates(f *Flag, prefix string, sc *Subcommand) []Candidate {
+	if f.CompletionFunc != nil {
+		return withPrefix(f.CompletionFunc(prefix, sc), prefix)
+	}
+	var candidates []Candidate
+	for _, choice := range f.choices() {
+		if strings.HasPrefix(choice, prefix) {
+			candidates = append(candidates, Candidate{Value: choice})
+		}
+	}
+	return candidates
+}
+
+// withPrefix returns the candidates that start with prefix, so that
+// completion functions do not have to filter their own results.
+func withPrefix(candidates []Candidate, prefix string) []Candidate {
+	var matching []Candidate
+	for _, c := range candidates {
+		if strings.HasPrefix(c.Value, prefix) {
+			matching = append(matching, c)
+		}
+	}
+	return matching
+}
+
+// writeCandidates answers the hidden completion command.  Each candidate is
+// written on its own line, followed by a tab and its description when it
+// has one.  The last argument is the word being completed.
+func (p *Parser) writeCandidates(w io.Writer, args []string) {
+	var cursor string
+	if len(args) > 0 {
+		cursor = args[len(args)-1]
+		args = args[:len(args)-1]
+	}
+	for _, c := range p.Complete(args, cursor) {
+		description := strings.Join(strings.Fields(c.Description), " ")
+		if description == "" {
+			fmt.Fprintln(w, c.Value)
+			continue
+		}
+		fmt.Fprintln(w, c.Value+"\t"+description)
+	}
+}
+
+// Complete returns the completion candidates of the default parser for
+// cursorWord when it follows args on the command line.
+func Complete(args []string, cursorWord string) []Candidate {
+	return DefaultParser.Complete(args, cursorWord)
+}
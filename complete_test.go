@@ -0,0 +1,78 @@
+package flaggy_test
+
+import (
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+// candidateValues returns just the values of the candidates for easy comparison.
+func candidateValues(candidates []flaggy.Candidate) []string {
+	var values []string
+	for _, c := range candidates {
+		values = append(values, c.Value)
+	}
+	return values
+}
+
+func TestParserComplete(t *testing.T) {
+	p := newNestedCompletionParser()
+	tests := []struct {
+		args   []string
+		cursor string
+		want   []string
+	}{
+		{nil, "", []string{"list", "remote"}},
+		{nil, "r", []string{"remote"}},
+		{nil, "--f", []string{"--format"}},
+		{[]string{"--format", "remote"}, "l", []string{"list"}},
+		{[]string{"remote"}, "", []string{"list"}},
+		{[]string{"remote", "list"}, "-", []string{"--all", "-a", "--region", "--format", "-f", "--verbose"}},
+		{[]string{"remote", "list", "--", "x"}, "-", nil},
+		{[]string{"-h", "remote"}, "", []string{"list"}},
+	}
+	for _, tt := range tests {
+		got := candidateValues(p.Complete(tt.args, tt.cursor))
+		if len(got) != len(tt.want) {
+			t.Fatalf("Complete(%q, %q) = %q, want %q", tt.args, tt.cursor, got, tt.want)
+		}
+		for i := range got {
+			if got[i] != tt.want[i] {
+				t.Fatalf("Complete(%q, %q) = %q, want %q", tt.args, tt.cursor, got, tt.want)
+			}
+		}
+	}
+}
+
+func TestCompleteDescriptions(t *testing.T) {
+	p := newNestedCompletionParser()
+	got := p.Complete([]string{"remote"}, "l")
+	if len(got) != 1 || got[0].Description != "List remotes" {
+		t.Fatalf("expected the nested list subcommand with its description, got %+v", got)
+	}
+}
+
+func TestCompleteLeavesParserUnparsed(t *testing.T) {
+	p := newNestedCompletionParser()
+	p.Complete([]string{"--format", "json", "remote"}, "")
+	p.Complete([]string{"remote", "list"}, "-")
+	if p.Changed("format") {
+		t.Fatal("expected completion not to assign flag values")
+	}
+	if err := p.ParseArgs([]string{"remote", "list", "--all"}); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompletePOSIXClusters(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.POSIXShortFlags = true
+	var verbose bool
+	var format string
+	p.Bool(&verbose, "v", "verbose", "Verbose output")
+	p.Enum(&format, "f", "format", "Output format", []string{"json", "yaml"})
+	got := candidateValues(p.Complete([]string{"-vf"}, "y"))
+	if len(got) != 1 || got[0] != "yaml" {
+		t.Fatalf("expected the clustered flag's value to be completed, got %q", got)
+	}
+}
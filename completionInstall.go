@@ -0,0 +1,312 @@
+package flaggy
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// completionTarget describes where the completion script for a shell is
+// installed.  Shells that do not load completion files from a directory
+// also get a line in their config file that loads the script.
+type completionTarget struct {
+	Path   string // the file the script is written to
+	Config string // the config file that loads the script, if one is needed
+	Line   string // the line added to Config
+	Hint   string // printed after installing when the shell needs more setup
+}
+
+// detectShell returns the name of the user's shell from $SHELL, or an
+// empty string when it is not a supported shell.
+func detectShell() string {
+	switch shell := filepath.Base(os.Getenv("SHELL")); shell {
+	case "bash", "zsh", "fish":
+		return shell
+	case "nu":
+		return "nushell"
+	case "pwsh", "powershell":
+		return "powershell"
+	}
+	return ""
+}
+
+// xdgDir returns the directory named by an XDG environment variable, or the
+// fallback inside the home directory when it is not set.
+func xdgDir(env string, fallback ...string) (string, error) {
+	if dir := os.Getenv(env); dir != "" {
+		return dir, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(append([]string{home}, fallback...)...), nil
+}
+
+// completionTargetFor returns the standard location of the completion
+// script of the named program for the shell.
+func completionTargetFor(shell string, name string) (completionTarget, error) {
+	var target completionTarget
+	switch shell {
+	case "bash":
+		data, err := xdgDir("XDG_DATA_HOME", ".local", "share")
+		if err != nil {
+			return target, err
+		}
+		target.Path = filepath.Join(data, "bash-completion", "completions", name)
+	case "zsh":
+		dir, err := zshCompletionDir()
+		if err != nil {
+			return target, err
+		}
+		target.Path = filepath.Join(dir, "_"+name)
+		if !inZshFpath(dir) {
+			target.Hint = "Add fpath=(" + dir + " $fpath) to your .zshrc before compinit is called."
+		}
+	case "fish":
+		config, err := xdgDir("XDG_CONFIG_HOME", ".config")
+		if err != nil {
+			return target, err
+		}
+		target.Path = filepath.Join(config, "fish", "completions", name+".fish")
+	case "nushell":
+		config, err := xdgDir("XDG_CONFIG_HOME", ".config")
+		if err != nil {
+			return target, err
+		}
+		target.Path = filepath.Join(config, "nushell", "completions", name+".nu")
+		target.Config = filepath.Join(config, "nushell", "config.nu")
+		target.Line = "source \"" + target.Path + "\""
+	case "powershell":
+		config, err := xdgDir("XDG_CONFIG_HOME", ".config")
+		if err != nil {
+			return target, err
+		}
+		target.Path = filepath.Join(config, "powershell", "completions", name+".ps1")
+		target.Config = filepath.Join(config, "powershell", "Microsoft.PowerShell_profile.ps1")
+		target.Line = ". \"" + target.Path + "\""
+	default:
+		return target, errors.New("unsupported shell: " + shell)
+	}
+	return target, nil
+}
+
+// zshCompletionDir returns the first directory of $FPATH inside the home
+// directory, where zsh already looks for completion functions.  When there
+// is none, a site-functions directory in the XDG data directory is used.
+// zsh does not export FPATH by default, so it is only seen when the user
+// exports it from their .zshrc.
+func zshCompletionDir() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	for _, dir := range filepath.SplitList(os.Getenv("FPATH")) {
+		if strings.HasPrefix(dir, home+string(filepath.Separator)) {
+			return dir, nil
+		}
+	}
+	data, err := xdgDir("XDG_DATA_HOME", ".local", "share")
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(data, "zsh", "site-functions"), nil
+}
+
+// inZshFpath reports whether dir is listed in the exported $FPATH.
+func inZshFpath(dir string) bool {
+	for _, d := range filepath.SplitList(os.Getenv("FPATH")) {
+		if d == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// completionScript returns the completion script of the parser for the
+// shell, or an empty string when the shell is not supported.
+func completionScript(p *Parser, shell string) string {
+	switch strings.ToLower(shell) {
+	case "bash":
+		return GenerateBashCompletion(p)
+	case "zsh":
+		return GenerateZshCompletion(p)
+	case "fish":
+		return GenerateFishCompletion(p)
+	case "powershell":
+		return GeneratePowerShellCompletion(p)
+	case "nushell":
+		return GenerateNushellCompletion(p)
+	}
+	return ""
+}
+
+// InstallCompletion writes the completion script for the shell to the
+// standard location that the shell loads completions from.  Shells that
+// need it also get a line in their config file that loads the script.  When
+// dryRun is true, the changes are only described.  What was done is written
+// to w.
+func (p *Parser) InstallCompletion(w io.Writer, shell string, dryRun bool) error {
+	target, err := completionTargetFor(shell, p.Name)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Fprintf(w, "Would write %s completion for %s to %s\n", shell, p.Name, target.Path)
+		if target.Config != "" {
+			fmt.Fprintf(w, "Would add %s to %s\n", target.Line, target.Config)
+		}
+		if target.Hint != "" {
+			fmt.Fprintln(w, target.Hint)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target.Path), 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(target.Path, []byte(completionScript(p, shell)), 0o644); err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "Installed %s completion for %s to %s\n", shell, p.Name, target.Path)
+	if target.Config != "" {
+		added, err := addConfigLine(target.Config, target.Line)
+		if err != nil {
+			return err
+		}
+		if added {
+			fmt.Fprintf(w, "Added %s to %s\n", target.Line, target.Config)
+		}
+	}
+	if target.Hint != "" {
+		fmt.Fprintln(w, target.Hint)
+	}
+	return nil
+}
+
+// UninstallCompletion removes the completion script for the shell written
+// by InstallCompletion, along with the line that loads it from the shell's
+// config file.  When dryRun is true, the changes are only described.
+func (p *Parser) UninstallCompletion(w io.Writer, shell string, dryRun bool) error {
+	target, err := completionTargetFor(shell, p.Name)
+	if err != nil {
+		return err
+	}
+	if dryRun {
+		fmt.Fprintf(w, "Would remove %s\n", target.Path)
+		if target.Config != "" {
+			fmt.Fprintf(w, "Would remove %s from %s\n", target.Line, target.Config)
+		}
+		return nil
+	}
+
+	err = os.Remove(target.Path)
+	switch {
+	case errors.Is(err, os.ErrNotExist):
+		fmt.Fprintf(w, "No %s completion for %s is installed at %s\n", shell, p.Name, target.Path)
+	case err != nil:
+		return err
+	default:
+		fmt.Fprintf(w, "Removed %s\n", target.Path)
+	}
+	if target.Config != "" {
+		removed, err := removeConfigLine(target.Config, target.Line)
+		if err != nil {
+			return err
+		}
+		if removed {
+			fmt.Fprintf(w, "Removed %s from %s\n", target.Line, target.Config)
+		}
+	}
+	return nil
+}
+
+// addConfigLine appends line to the config file unless it is already there.
+// The file is created when it does not exist.
+func addConfigLine(config string, line string) (bool, error) {
+	contents, err := os.ReadFile(config)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	for _, existing := range strings.Split(string(contents), "\n") {
+		if strings.TrimSpace(existing) == line {
+			return false, nil
+		}
+	}
+	if len(contents) > 0 && !strings.HasSuffix(string(contents), "\n") {
+		contents = append(contents, '\n')
+	}
+	contents = append(contents, line+"\n"...)
+	if err := os.MkdirAll(filepath.Dir(config), 0o755); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(config, contents, 0o644)
+}
+
+// removeConfigLine removes every copy of line from the config file.
+func removeConfigLine(config string, line string) (bool, error) {
+	contents, err := os.ReadFile(config)
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	var kept []string
+	var removed bool
+	for _, existing := range strings.SplitAfter(string(contents), "\n") {
+		if strings.TrimSpace(existing) == line {
+			removed = true
+			continue
+		}
+		kept = append(kept, existing)
+	}
+	if !removed {
+		return false, nil
+	}
+	return true, os.WriteFile(config, []byte(strings.Join(kept, "")), 0o644)
+}
+
+// runCompletionInstall handles the completion install and uninstall
+// commands.  The shell is detected from $SHELL when it is not supplied, and
+// --dry-run describes the changes without making them.  Parsing stops
+// afterwards as the parser's ErrorHandling describes.
+func (p *Parser) runCompletionInstall(action string, args []string) error {
+	var shell string
+	var dryRun bool
+	for _, a := range args {
+		switch {
+		case a == "--dry-run" || a == "-dry-run":
+			dryRun = true
+		case shell == "":
+			shell = strings.ToLower(a)
+		default:
+			fmt.Fprintf(os.Stderr, "Unexpected argument for completion %s: %s\n", action, a)
+			return p.stopParsing(2, &ExitError{Code: 2, Err: errors.New("unexpected argument for completion " + action + ": " + a)})
+		}
+	}
+	if shell == "" {
+		shell = detectShell()
+		if shell == "" {
+			fmt.Fprintf(os.Stderr, "Unable to detect your shell from $SHELL. Please specify one of: %s\n", completionShellList())
+			return p.stopParsing(2, &ExitError{Code: 2, Err: errors.New("unable to detect shell for completion " + action)})
+		}
+	}
+	if !isSupportedCompletionShell(shell) {
+		fmt.Fprintf(os.Stderr, "Unsupported shell specified for completion: %s\nSupported shells: %s\n", shell, completionShellList())
+		return p.stopParsing(2, &ExitError{Code: 2, Err: errors.New("unsupported shell specified for completion: " + shell)})
+	}
+
+	run := p.InstallCompletion
+	if action == "uninstall" {
+		run = p.UninstallCompletion
+	}
+	if err := run(os.Stdout, shell, dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "Unable to %s %s completion: %s\n", action, shell, err)
+		return p.stopParsing(1, &ExitError{Code: 1, Err: err})
+	}
+	return p.stopParsing(0, ErrCompletion)
+}
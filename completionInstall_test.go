@@ -0,0 +1,138 @@
+package flaggy_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+// useTempHome points the home and XDG directories at a temporary directory so
+// completion scripts are installed there.
+func useTempHome(t *testing.T, shell string) string {
+	t.Helper()
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("FPATH", "")
+	t.Setenv("SHELL", shell)
+	return home
+}
+
+// runCompletionCommand runs a completion command and fails unless it succeeds.
+func runCompletionCommand(t *testing.T, args ...string) string {
+	t.Helper()
+	stdout, stderr, err := runParser(t, flaggy.NewParser("starfleet"), append([]string{"completion"}, args...))
+	if !errors.Is(err, flaggy.ErrCompletion) {
+		t.Fatalf("expected completion %q to succeed: %v %s", args, err, stderr)
+	}
+	return stdout
+}
+
+func TestCompletionInstallDetectsShell(t *testing.T) {
+	home := useTempHome(t, "/usr/bin/bash")
+	out := runCompletionCommand(t, "install")
+	path := filepath.Join(home, ".local", "share", "bash-completion", "completions", "starfleet")
+	if !strings.Contains(out, "Installed bash completion for starfleet to "+path) {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	script, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(script), "complete -o default -F _starfleet_complete starfleet") {
+		t.Fatalf("expected the bash script to be installed:\n%s", script)
+	}
+}
+
+func TestCompletionInstallLocations(t *testing.T) {
+	tests := []struct {
+		shell string
+		path  []string
+	}{
+		{"zsh", []string{".local", "share", "zsh", "site-functions", "_starfleet"}},
+		{"fish", []string{".config", "fish", "completions", "starfleet.fish"}},
+		{"nushell", []string{".config", "nushell", "completions", "starfleet.nu"}},
+		{"powershell", []string{".config", "powershell", "completions", "starfleet.ps1"}},
+	}
+	for _, tt := range tests {
+		home := useTempHome(t, "")
+		runCompletionCommand(t, "install", tt.shell)
+		if _, err := os.Stat(filepath.Join(append([]string{home}, tt.path...)...)); err != nil {
+			t.Fatalf("expected %s completion to be installed: %v", tt.shell, err)
+		}
+	}
+}
+
+func TestCompletionInstallZshFpath(t *testing.T) {
+	home := useTempHome(t, "/bin/zsh")
+	dir := filepath.Join(home, ".zfunc")
+	t.Setenv("FPATH", "/usr/share/zsh/functions:"+dir)
+	out := runCompletionCommand(t, "install")
+	if _, err := os.Stat(filepath.Join(dir, "_starfleet")); err != nil {
+		t.Fatalf("expected the zsh completion in the fpath directory: %v", err)
+	}
+	if strings.Contains(out, "fpath=") {
+		t.Fatalf("expected no fpath hint for a directory already in fpath: %s", out)
+	}
+}
+
+func TestCompletionInstallDryRun(t *testing.T) {
+	home := useTempHome(t, "/usr/local/bin/fish")
+	out := runCompletionCommand(t, "install", "--dry-run")
+	path := filepath.Join(home, ".config", "fish", "completions", "starfleet.fish")
+	if !strings.Contains(out, "Would write fish completion for starfleet to "+path) {
+		t.Fatalf("unexpected output: %s", out)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Fatalf("expected a dry run not to write the script: %v", err)
+	}
+
+	home = useTempHome(t, "/bin/zsh")
+	out = runCompletionCommand(t, "install", "--dry-run")
+	dir := filepath.Join(home, ".local", "share", "zsh", "site-functions")
+	if !strings.Contains(out, "Add fpath=("+dir+" $fpath) to your .zshrc") {
+		t.Fatalf("expected the fpath hint in a dry run: %s", out)
+	}
+}
+
+func TestCompletionUninstall(t *testing.T) {
+	home := useTempHome(t, "/usr/bin/nu")
+	config := filepath.Join(home, ".config", "nushell", "config.nu")
+	if err := os.MkdirAll(filepath.Dir(config), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(config, []byte("$env.config.show_banner = false"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	runCompletionCommand(t, "install")
+	runCompletionCommand(t, "install")
+	contents, _ := os.ReadFile(config)
+	if strings.Count(string(contents), "starfleet.nu") != 1 {
+		t.Fatalf("expected the script to be sourced once:\n%s", contents)
+	}
+
+	runCompletionCommand(t, "uninstall")
+	contents, _ = os.ReadFile(config)
+	if string(contents) != "$env.config.show_banner = false\n" {
+		t.Fatalf("expected only the source line to be removed:\n%s", contents)
+	}
+	if _, err := os.Stat(filepath.Join(home, ".config", "nushell", "completions", "starfleet.nu")); !os.IsNotExist(err) {
+		t.Fatalf("expected the script to be removed: %v", err)
+	}
+}
+
+func TestCompletionInstallUnknownShell(t *testing.T) {
+	useTempHome(t, "/bin/tcsh")
+	_, stderr, err := runParser(t, flaggy.NewParser("starfleet"), []string{"completion", "install"})
+	if code := flaggy.ExitCode(err); code != 2 {
+		t.Fatalf("expected exit code 2 when the shell can not be detected: %d %v", code, err)
+	}
+	if !strings.Contains(stderr, "Unable to detect your shell") {
+		t.Fatalf("unexpected stderr: %s", stderr)
+	}
+}
@@ -0,0 +1,260 @@
+package flaggy
+
+import (
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// GenerateZshCompletion returns a zsh completion script for the parser.
+// Every subcommand gets a function that describes its flags to _arguments,
+// with their descriptions, value types, and mutually exclusive flags, and
+// that offers child subcommands with _describe at their positions.  The
+// values of flags and positional values are completed by the hidden
+// __complete command.  The script can be sourced, or installed in $fpath as
+// _<name> to be autoloaded.
+func GenerateZshCompletion(p *Parser) string {
+	var b strings.Builder
+	name := sanitizeName(p.Name)
+	valuesFunc := "_" + name + "_values"
+
+	b.WriteString("#compdef " + p.Name + "\n\n")
+
+	b.WriteString("# " + valuesFunc + " offers the values that " + p.Name + " completes itself, or\n")
+	b.WriteString("# files when there are none.\n")
+	b.WriteString(valuesFunc + "() {\n")
+	b.WriteString("    local -a candidates\n")
+	b.WriteString("    local line value description\n")
+	b.WriteString("    for line in \"${(@f)$(\"$_" + name + "_program\" " + completeCommand + " \"${_" + name + "_words[@]}\" \"$_" + name + "_current\" 2>/dev/null)}\"; do\n")
+	b.WriteString("        [[ -z $line ]] && continue\n")
+	b.WriteString("        value=${line%%$'\\t'*}\n")
+	b.WriteString("        value=${value//:/\\\\:}\n")
+	b.WriteString("        if [[ $line == *$'\\t'* ]]; then\n")
+	b.WriteString("            description=${line#*$'\\t'}\n")
+	b.WriteString("            candidates+=(\"$value:$description\")\n")
+	b.WriteString("        else\n")
+	b.WriteString("            candidates+=(\"$value\")\n")
+	b.WriteString("        fi\n")
+	b.WriteString("    done\n")
+	b.WriteString("    if (( ${#candidates} )); then\n")
+	b.WriteString("        _describe -t values 'value' candidates\n")
+	b.WriteString("    else\n")
+	b.WriteString("        _files\n")
+	b.WriteString("    fi\n")
+	b.WriteString("}\n\n")
+
+	writeZshFunction(&b, p, []*Subcommand{&p.Subcommand}, "_"+name+"_cmd", valuesFunc)
+
+	b.WriteString("_" + name + "() {\n")
+	b.WriteString("    local _" + name + "_program=$words[1]\n")
+	b.WriteString("    local -a _" + name + "_words=(\"${(@Q)words[2,CURRENT-1]}\")\n")
+	b.WriteString("    local _" + name + "_current=${(Q)words[CURRENT]}\n")
+	b.WriteString("    _" + name + "_cmd\n")
+	b.WriteString("}\n\n")
+	// when autoloaded from $fpath as _<program>, this file is the body of the
+	// completion function, so complete now instead of only registering it
+	b.WriteString("if [ \"$funcstack[1]\" = \"_" + p.Name + "\" ]; then\n")
+	b.WriteString("    _" + name + " \"$@\"\n")
+	b.WriteString("else\n")
+	b.WriteString("    compdef _" + name + " " + p.Name + "\n")
+	b.WriteString("fi\n")
+	return b.String()
+}
+
+// writeZshFunction writes the completion function for the last subcommand
+// in path, followed by the functions of its children.  Once _arguments has
+// handled the flags, the remaining words are walked to find the position
+// being completed, handing off to a child's function when its name is at
+// the child's position.
+func writeZshFunction(b *strings.Builder, p *Parser, path []*Subcommand, funcName string, valuesFunc string) {
+	sc := path[len(path)-1]
+	scopes := []*Subcommand{sc}
+	if sc != &p.Subcommand {
+		scopes = append(scopes, &p.Subcommand)
+	}
+
+	var valueFlags []string
+	b.WriteString(funcName + "() {\n")
+	b.WriteString("    local curcontext=\"$curcontext\" state line ret=1\n")
+	b.WriteString("    _arguments -C \\\n")
+	for _, scope := range scopes {
+		for _, f := range scope.Flags {
+			if f.hidden() {
+				continue
+			}
+			b.WriteString("        " + zshFlagSpec(scope, f, valuesFunc) + " \\\n")
+			if !f.isBool() {
+				valueFlags = append(valueFlags, flagCompletionNames(f)...)
+			}
+		}
+	}
+	b.WriteString("        '*:: :->args' && ret=0\n")
+	b.WriteString("    case $state in\n")
+	b.WriteString("        args)\n")
+	b.WriteString("            local -i position=1 i\n")
+	b.WriteString("            local -a value_flags=(" + strings.Join(valueFlags, " ") + ")\n")
+	b.WriteString("            for (( i = 1; i < CURRENT; i++ )); do\n")
+	b.WriteString("                case $words[i] in\n")
+	b.WriteString("                    --)\n")
+	b.WriteString("                        _files && ret=0\n")
+	b.WriteString("                        return ret\n")
+	b.WriteString("                        ;;\n")
+	b.WriteString("                    -*=*) ;;\n")
+	b.WriteString("                    -*) (( ${value_flags[(Ie)$words[i]]} )) && (( i++ )) ;;\n")
+	b.WriteString("                    *)\n")
+	if len(sc.Subcommands) > 0 {
+		b.WriteString("                        case \"$position:$words[i]\" in\n")
+	}
+	for _, child := range sc.Subcommands {
+		var patterns []string
+		for _, n := range append([]string{child.Name, child.ShortName}, child.Aliases...) {
+			if n != "" {
+				patterns = append(patterns, strconv.Itoa(child.Position)+":"+zshQuote(n))
+			}
+		}
+		b.WriteString("                            " + strings.Join(patterns, "|") + ")\n")
+		b.WriteString("                                shift $(( i - 1 )) words\n")
+		b.WriteString("                                (( CURRENT -= i - 1 ))\n")
+		b.WriteString("                                " + zshFunctionName(funcName, child) + "\n")
+		b.WriteString("                                return\n")
+		b.WriteString("                                ;;\n")
+	}
+	if len(sc.Subcommands) > 0 {
+		b.WriteString("                        esac\n")
+	}
+	b.WriteString("                        (( position++ ))\n")
+	b.WriteString("                        ;;\n")
+	b.WriteString("                esac\n")
+	b.WriteString("            done\n")
+	b.WriteString("            case $position in\n")
+	for _, position := range zshSubcommandPositions(sc) {
+		b.WriteString("                " + strconv.Itoa(position) + ")\n")
+		b.WriteString("                    local -a subcommands=(\n")
+		for _, child := range sc.Subcommands {
+			if child.Position != position || child.hidden() {
+				continue
+			}
+			entry := strings.ReplaceAll(child.Name, ":", "\\:")
+			if child.Description != "" {
+				entry += ":" + child.Description
+			}
+			b.WriteString("                        " + zshQuote(entry) + "\n")
+		}
+		b.WriteString("                    )\n")
+		b.WriteString("                    _describe -t commands 'subcommand' subcommands && ret=0\n")
+		b.WriteString("                    ;;\n")
+	}
+	b.WriteString("                *)\n")
+	b.WriteString("                    " + valuesFunc + " && ret=0\n")
+	b.WriteString("                    ;;\n")
+	b.WriteString("            esac\n")
+	b.WriteString("            ;;\n")
+	b.WriteString("    esac\n")
+	b.WriteString("    return ret\n")
+	b.WriteString("}\n\n")
+
+	for _, child := range sc.Subcommands {
+		childPath := append(append([]*Subcommand{}, path...), child)
+		writeZshFunction(b, p, childPath, zshFunctionName(funcName, child), valuesFunc)
+	}
+}
+
+// zshFunctionName returns the name of the completion function of a child
+// subcommand.
+func zshFunctionName(parentFunc string, child *Subcommand) string {
+	return parentFunc + "_" + sanitizeName(child.Name)
+}
+
+// zshSubcommandPositions returns the positions of the visible child
+// subcommands in ascending order, without duplicates.
+func zshSubcommandPositions(sc *Subcommand) []int {
+	var positions []int
+	for _, child := range sc.Subcommands {
+		if !child.hidden() && !slices.Contains(positions, child.Position) {
+			positions = append(positions, child.Position)
+		}
+	}
+	slices.Sort(positions)
+	return positions
+}
+
+// zshFlagSpec returns the _arguments spec of a flag.  Flags that can not be
+// repeated exclude themselves, mutually exclusive flags exclude each other,
+// and flags that take a value name its type.
+func zshFlagSpec(sc *Subcommand, f *Flag, valuesFunc string) string {
+	t := reflect.TypeOf(f.AssignmentVar)
+	repeatable := f.isSlice() || f.isCounter() || (t.Kind() == reflect.Pointer && t.Elem().Kind() == reflect.Map)
+	var excluded []string
+	if !repeatable {
+		excluded = append(excluded, flagCompletionNames(f)...)
+	}
+	for _, other := range sc.exclusiveWith(f) {
+		excluded = append(excluded, flagCompletionNames(other)...)
+	}
+
+	var names []string
+	for _, n := range flagCompletionNames(f) {
+		if !f.isBool() && strings.HasPrefix(n, "--") {
+			n += "="
+		}
+		names = append(names, n)
+	}
+
+	spec := "'"
+	if len(excluded) > 0 {
+		spec += "(" + strings.Join(excluded, " ") + ")"
+	}
+	if repeatable {
+		spec += "*"
+	}
+	if len(names) == 1 {
+		spec += names[0]
+	} else {
+		spec += "'{" + strings.Join(names, ",") + "}'"
+	}
+
+	spec += "[" + zshEscapeSpec(f.Description) + "]"
+	if !f.isBool() {
+		action := valuesFunc
+		if choices := f.choices(); len(choices) > 0 && f.CompletionFunc == nil {
+			action = "(" + strings.Join(choices, " ") + ")"
+		}
+		spec += ":" + strings.ReplaceAll(zshEscapeSpec(flagValueType(f)), ":", "\\:") + ":" + action
+	}
+	return spec + "'"
+}
+
+// flagValueType returns the name of the type of value a flag takes, like
+// "int" or "duration".  Slices are named by their elements.
+func flagValueType(f *Flag) string {
+	if v, ok := f.AssignmentVar.(Value); ok {
+		return v.Type()
+	}
+	t := reflect.TypeOf(f.AssignmentVar)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch {
+	case t.Kind() == reflect.Map:
+		return "key=value"
+	case t.Kind() == reflect.Slice && t.Name() == "":
+		t = t.Elem()
+	}
+	if t.Name() == "" {
+		return t.String()
+	}
+	return strings.ToLower(t.Name())
+}
+
+// zshEscapeSpec escapes the brackets around the description in an
+// _arguments spec, and the single quotes that surround the spec.
+func zshEscapeSpec(s string) string {
+	s = strings.NewReplacer("\\", "\\\\", "[", "\\[", "]", "\\]").Replace(s)
+	return strings.ReplaceAll(s, "'", "'\\''")
+}
+
+// zshQuote single quotes a word for zsh.
+func zshQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", "'\\''") + "'"
+}
@@ -1,6 +1,7 @@
 package flaggy_test
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -25,13 +26,41 @@ func newCompletionParser() (*flaggy.Parser, []string) {
 	return p, commands
 }
 
-// verifyCompletionCoverage ensures that each fictitious command surfaces within the
-// produced completion output so developers can trust the generator to describe real CLIs.
-func verifyCompletionCoverage(t *testing.T, output string, commands []string, shell string) {
+// completeWords runs the hidden completion command that the generated scripts call back
+// into and returns the candidate lines it prints for the final word.
+func completeWords(t *testing.T, p *flaggy.Parser, words ...string) []string {
 	t.Helper()
+	stdout, stderr, err := runParser(t, p, append([]string{"__complete"}, words...))
+	if !errors.Is(err, flaggy.ErrCompletion) {
+		t.Fatalf("expected completion to succeed: %v %s", err, stderr)
+	}
+	if stdout == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(stdout, "\n"), "\n")
+}
+
+// verifyCandidates compares completion candidates with the expected lines in order.
+func verifyCandidates(t *testing.T, got []string, want ...string) {
+	t.Helper()
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected candidates %q, got %q", want, got)
+	}
+}
+
+// verifyCallback ensures that a generated script asks the program for candidates
+// instead of embedding them.
+func verifyCallback(t *testing.T, output string, commands []string, shell string, registration string) {
+	t.Helper()
+	if !strings.Contains(output, "__complete") {
+		t.Fatalf("expected %s completion to call back into the program: %s", shell, output)
+	}
+	if !strings.Contains(output, registration) {
+		t.Fatalf("expected %s completion to contain %q: %s", shell, registration, output)
+	}
 	for _, name := range commands {
-		if !strings.Contains(output, name) {
-			t.Fatalf("expected %s completion to list command %s: %s", shell, name, output)
+		if strings.Contains(output, name) {
+			t.Fatalf("expected %s completion not to embed command %s: %s", shell, name, output)
 		}
 	}
 }
@@ -40,15 +69,9 @@ func verifyCompletionCoverage(t *testing.T, output string, commands []string, sh
 func TestGenerateBashCompletion(t *testing.T) {
 	p, commands := newCompletionParser()
 	out := flaggy.GenerateBashCompletion(p)
-	verifyCompletionCoverage(t, out, commands, "bash")
-	if !strings.Contains(out, "--warp") {
-		t.Fatalf("expected bash completion to include long flag name: %s", out)
-	}
-	if !strings.Contains(out, "-w") {
-		t.Fatalf("expected bash completion to include short flag name: %s", out)
-	}
-	if !strings.Contains(out, "sector") {
-		t.Fatalf("expected bash completion to include positional name: %s", out)
+	verifyCallback(t, out, commands, "bash", "complete -o default -F _starfleet_complete starfleet")
+	if !strings.Contains(out, "_get_comp_words_by_ref -n =: cur words cword") {
+		t.Fatalf("expected bash completion to join words split on = and :: %s", out)
 	}
 }
 
@@ -56,15 +79,81 @@ func TestGenerateBashCompletion(t *testing.T) {
 func TestGenerateZshCompletion(t *testing.T) {
 	p, commands := newCompletionParser()
 	out := flaggy.GenerateZshCompletion(p)
-	verifyCompletionCoverage(t, out, commands, "zsh")
-	if !strings.Contains(out, "--warp") {
-		t.Fatalf("expected zsh completion to include long flag name: %s", out)
+	for _, want := range []string{
+		"if [ \"$funcstack[1]\" = \"_starfleet\" ]; then\n    _starfleet \"$@\"\nelse\n    compdef _starfleet starfleet\nfi\n",
+		"__complete \"${_starfleet_words[@]}\"",
+		"${(@Q)words[2,CURRENT-1]}",
+		"'(--warp -w)'{--warp=,-w}'[Enable warp calibration during deployment]:string:_starfleet_values'",
+		"_describe -t commands 'subcommand' subcommands",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected zsh completion to contain %q: %s", want, out)
+		}
 	}
-	if !strings.Contains(out, "-w") {
-		t.Fatalf("expected zsh completion to include short flag name: %s", out)
+	for _, name := range commands {
+		if !strings.Contains(out, "'"+name+":Handle "+name+" operations'") {
+			t.Fatalf("expected zsh completion to describe command %s: %s", name, out)
+		}
+		if !strings.Contains(out, "_starfleet_cmd_"+name+"() {") {
+			t.Fatalf("expected zsh completion to define a function for %s: %s", name, out)
+		}
 	}
-	if !strings.Contains(out, "sector") {
-		t.Fatalf("expected zsh completion to include positional name: %s", out)
+}
+
+// TestGenerateZshCompletionFlagSpecs verifies that zsh flag specs name value types,
+// exclude conflicting flags, allow slices to repeat, and list enum choices.
+func TestGenerateZshCompletionFlagSpecs(t *testing.T) {
+	p := flaggy.NewParser("starfleet")
+	var crew []string
+	var warp int
+	var file, url, format string
+	var dock bool
+	p.StringSlice(&crew, "c", "crew", "Crew members")
+	p.Int(&warp, "", "warp", "Warp factor [1-9]")
+	p.String(&file, "", "file", "Read from a file")
+	p.String(&url, "", "url", "Read from a url")
+	p.Bool(&dock, "", "dock", "Dock the ship")
+	p.MutuallyExclusive("file", "url")
+	p.Enum(&format, "f", "format", "Output format", []string{"json", "yaml"})
+	out := flaggy.GenerateZshCompletion(p)
+	for _, want := range []string{
+		"'*'{--crew=,-c}'[Crew members]:string:_starfleet_values'",
+		"'(--warp)--warp=[Warp factor \\[1-9\\]]:int:_starfleet_values'",
+		"'(--file --url)--file=[Read from a file]:string:_starfleet_values'",
+		"'(--dock)--dock[Dock the ship]'",
+		"'(--format -f)'{--format=,-f}'[Output format]:enum:(json yaml)'",
+		"local -a value_flags=(--crew -c --warp --file --url --format -f)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected zsh completion to contain %q: %s", want, out)
+		}
+	}
+}
+
+// TestGenerateZshCompletionPositions verifies that subcommands are only offered and
+// dispatched at their own positions.
+func TestGenerateZshCompletionPositions(t *testing.T) {
+	p := flaggy.NewParser("starfleet")
+	var ship string
+	p.AddPositionalValue(&ship, "ship", 1, false, "Ship name")
+	orbit := flaggy.NewSubcommand("orbit")
+	orbit.Description = "Enter orbit"
+	p.AttachSubcommand(orbit, 2)
+	hidden := flaggy.NewSubcommand("selfdestruct")
+	hidden.Hidden = true
+	p.AttachSubcommand(hidden, 2)
+	out := flaggy.GenerateZshCompletion(p)
+	for _, want := range []string{
+		"2:'orbit')",
+		"                2)\n                    local -a subcommands=(\n                        'orbit:Enter orbit'\n                    )",
+		"2:'selfdestruct')",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected zsh completion to contain %q: %s", want, out)
+		}
+	}
+	if strings.Contains(out, "'selfdestruct:") || strings.Contains(out, "                1)\n") {
+		t.Fatalf("expected only visible subcommands at their position to be offered: %s", out)
 	}
 }
 
@@ -72,18 +161,9 @@ func TestGenerateZshCompletion(t *testing.T) {
 func TestGenerateFishCompletion(t *testing.T) {
 	p, commands := newCompletionParser()
 	out := flaggy.GenerateFishCompletion(p)
-	verifyCompletionCoverage(t, out, commands, "fish")
-	if !strings.Contains(out, "complete -c starfleet") {
-		t.Fatalf("expected fish completion to target starfleet: %s", out)
-	}
-	if !strings.Contains(out, "-l warp") {
-		t.Fatalf("expected fish completion to include long flag name: %s", out)
-	}
-	if !strings.Contains(out, "-s w") {
-		t.Fatalf("expected fish completion to include short flag name: %s", out)
-	}
-	if !strings.Contains(out, "sector") {
-		t.Fatalf("expected fish completion to include positional name: %s", out)
+	verifyCallback(t, out, commands, "fish", "complete -c starfleet -f -a '(__starfleet_complete)'")
+	if !strings.Contains(out, "__fish_complete_path (commandline -ct)") {
+		t.Fatalf("expected fish completion to fall back to files: %s", out)
 	}
 }
 
@@ -91,36 +171,134 @@ func TestGenerateFishCompletion(t *testing.T) {
 func TestGeneratePowerShellCompletion(t *testing.T) {
 	p, commands := newCompletionParser()
 	out := flaggy.GeneratePowerShellCompletion(p)
-	verifyCompletionCoverage(t, out, commands, "powershell")
-	if !strings.Contains(out, "Register-ArgumentCompleter") {
-		t.Fatalf("expected powershell completion to register completer: %s", out)
-	}
-	if !strings.Contains(out, "--warp") {
-		t.Fatalf("expected powershell completion to include long flag name: %s", out)
-	}
-	if !strings.Contains(out, "-w") {
-		t.Fatalf("expected powershell completion to include short flag name: %s", out)
-	}
-	if !strings.Contains(out, "sector") {
-		t.Fatalf("expected powershell completion to include positional name: %s", out)
-	}
+	verifyCallback(t, out, commands, "powershell", "Register-ArgumentCompleter -Native -CommandName 'starfleet'")
 }
 
 // TestGenerateNushellCompletion exercises Nushell completions with the fleet commands.
 func TestGenerateNushellCompletion(t *testing.T) {
 	p, commands := newCompletionParser()
 	out := flaggy.GenerateNushellCompletion(p)
-	verifyCompletionCoverage(t, out, commands, "nushell")
-	if !strings.Contains(out, "extern \"starfleet\"") {
-		t.Fatalf("expected nushell completion to expose extern signature: %s", out)
+	verifyCallback(t, out, commands, "nushell", "extern \"starfleet\"")
+	if !strings.Contains(out, "--warp(-w): string@\"nu-complete starfleet\"") {
+		t.Fatalf("expected nushell completion to complete flag values: %s", out)
 	}
-	if !strings.Contains(out, "\"--warp\"") {
-		t.Fatalf("expected nushell completion to include long flag name: %s", out)
+}
+
+// TestCompleteSubcommands verifies that the completion command offers subcommands with
+// their descriptions, filtered by the word being completed.
+func TestCompleteSubcommands(t *testing.T) {
+	p, _ := newCompletionParser()
+	verifyCandidates(t, completeWords(t, p, "de"),
+		"deploy\tHandle deploy operations",
+		"destroy\tHandle destroy operations",
+	)
+}
+
+// TestCompleteFlags verifies that flags are offered once a dash is typed, including the
+// root flags inside of subcommands.
+func TestCompleteFlags(t *testing.T) {
+	p, _ := newCompletionParser()
+	verifyCandidates(t, completeWords(t, p, "dock", "-"),
+		"--warp\tEnable warp calibration during deployment",
+		"-w\tEnable warp calibration during deployment",
+	)
+}
+
+// TestCompletionFunc verifies that flag and positional values are completed by their
+// CompletionFunc, with the partial word and subcommand supplied.
+func TestCompletionFunc(t *testing.T) {
+	var gotPrefix, gotContext string
+	newParser := func() *flaggy.Parser {
+		p := flaggy.NewParser("starfleet")
+		var ship, sector string
+		p.String(&ship, "s", "ship", "Ship to command").CompletionFunc = func(prefix string, ctx *flaggy.Subcommand) []flaggy.Candidate {
+			gotPrefix, gotContext = prefix, ctx.Name
+			return []flaggy.Candidate{{Value: "enterprise", Description: "NCC-1701"}, {Value: "defiant"}}
+		}
+		p.AddPositionalValue(&sector, "sector", 1, false, "Target sector")
+		p.PositionalFlags[0].CompletionFunc = func(prefix string, ctx *flaggy.Subcommand) []flaggy.Candidate {
+			return []flaggy.Candidate{{Value: prefix + "001"}, {Value: "vulcan"}}
+		}
+		return p
+	}
+
+	verifyCandidates(t, completeWords(t, newParser(), "--ship", "e"), "enterprise\tNCC-1701")
+	if gotPrefix != "e" || gotContext != "starfleet" {
+		t.Fatalf("unexpected completion arguments %q %q", gotPrefix, gotContext)
 	}
-	if !strings.Contains(out, "\"-w\"") {
-		t.Fatalf("expected nushell completion to include short flag name: %s", out)
+	verifyCandidates(t, completeWords(t, newParser(), "--ship=d"), "--ship=defiant")
+	verifyCandidates(t, completeWords(t, newParser(), "-s", "enterprise", "sol"), "sol001")
+}
+
+// newNestedCompletionParser builds a parser with subcommands that share a name at
+// different depths, so completion has to follow the whole command line.
+func newNestedCompletionParser() *flaggy.Parser {
+	p := flaggy.NewParser("starfleet")
+	var format, token, region, pattern string
+	var verbose, all bool
+	p.String(&format, "f", "format", "Output format")
+	p.Bool(&verbose, "", "verbose", "Verbose output")
+	p.String(&token, "", "token", "Access token").Hidden = true
+
+	list := flaggy.NewSubcommand("list")
+	list.Description = "List ships"
+	p.AttachSubcommand(list, 1)
+
+	remote := flaggy.NewSubcommand("remote")
+	remote.Description = "Manage remotes"
+	p.AttachSubcommand(remote, 1)
+
+	remoteList := flaggy.NewSubcommand("list")
+	remoteList.Description = "List remotes"
+	remoteList.Bool(&all, "a", "all", "Show all remotes")
+	remoteList.String(&region, "", "region", "Region to list")
+	remoteList.AddPositionalValue(&pattern, "pattern", 1, false, "Remote name pattern")
+	remote.AttachSubcommand(remoteList, 1)
+	return p
+}
+
+// TestCompleteAfterFlagValue verifies that the value of a flag is not mistaken for a
+// subcommand or positional value.
+func TestCompleteAfterFlagValue(t *testing.T) {
+	verifyCandidates(t, completeWords(t, newNestedCompletionParser(), "--format", "list", ""),
+		"list\tList ships",
+		"remote\tManage remotes",
+	)
+}
+
+// TestCompleteNestedSubcommands verifies that subcommands sharing a name at different
+// depths are told apart, even with flags between them.
+func TestCompleteNestedSubcommands(t *testing.T) {
+	verifyCandidates(t, completeWords(t, newNestedCompletionParser(), "remote", ""), "list\tList remotes")
+	verifyCandidates(t, completeWords(t, newNestedCompletionParser(), "-f", "json", "remote", "--verbose", "list", "-"),
+		"--all\tShow all remotes",
+		"-a\tShow all remotes",
+		"--region\tRegion to list",
+		"--format\tOutput format",
+		"-f\tOutput format",
+		"--verbose\tVerbose output",
+	)
+}
+
+// TestCompleteChildFlagBeforeSubcommand verifies that the value of a flag belonging to
+// a subcommand is skipped when the flag is typed before the subcommand's name.
+func TestCompleteChildFlagBeforeSubcommand(t *testing.T) {
+	verifyCandidates(t, completeWords(t, newNestedCompletionParser(), "remote", "--region", "eu", "list", "--r"),
+		"--region\tRegion to list",
+	)
+}
+
+// TestCompleteHidesHiddenAndPositionalNames verifies that hidden flags are never offered
+// and that positional names are not offered as literal words.
+func TestCompleteHidesHiddenAndPositionalNames(t *testing.T) {
+	for _, words := range [][]string{{"--"}, {"remote", "list", ""}} {
+		for _, line := range completeWords(t, newNestedCompletionParser(), words...) {
+			if strings.HasPrefix(line, "--token") || strings.HasPrefix(line, "pattern") {
+				t.Fatalf("unexpected candidate %q for %q", line, words)
+			}
+		}
 	}
-	if !strings.Contains(out, "\"sector\"") {
-		t.Fatalf("expected nushell completion to include positional name: %s", out)
+	if got := completeWords(t, newNestedCompletionParser(), "remote", "list", ""); len(got) != 0 {
+		t.Fatalf("expected no candidates for a positional value without a CompletionFunc: %q", got)
 	}
 }
@@ -0,0 +1,346 @@
+package flaggy
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// configEntry is a single key and value read from a config file.  Section
+// holds the names of the subcommands the key belongs to, starting below the
+// root parser.
+type configEntry struct {
+	File    string
+	Line    int
+	Section []string
+	Key     string
+	Value   string
+}
+
+// location formats the file and line of the entry for error messages.
+func (e configEntry) location() string {
+	return e.File + ":" + strconv.Itoa(e.Line)
+}
+
+// LoadConfigFile loads flag values from the config file at the specified
+// path.  Files ending in .json are read as JSON, where nested objects are
+// sections named after subcommands.  All other files are read as INI or
+// dotenv files, where [section] headers name subcommands and nested
+// subcommands are separated with dots, like [deploy.canary].
+//
+// Values from config files only fill in flags that were not supplied on the
+// command line or by the environment.  When called before parsing, the
+// values are held until ParseArgs runs.  Unknown keys are reported with
+// their file and line.
+func (p *Parser) LoadConfigFile(path string) error {
+	entries, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+	if err := p.checkConfigEntries(entries); err != nil {
+		return err
+	}
+	if !p.configApplied {
+		p.configEntries = append(p.configEntries, entries...)
+		return nil
+	}
+	return p.applyConfigEntries(entries)
+}
+
+// LoadConfigFile loads flag values for the default parser from the config
+// file at the specified path.
+func LoadConfigFile(path string) error {
+	return DefaultParser.LoadConfigFile(path)
+}
+
+// registerConfigFlag adds the built-in config file flag to the root parser
+// when the parser has one configured.
+func (p *Parser) registerConfigFlag() {
+	if p.ConfigFlag == "" || p.FlagExists(p.ConfigFlag) {
+		return
+	}
+	p.String(&p.configFile, "", p.ConfigFlag, "Loads flag values from a JSON, INI, or dotenv config file.")
+}
+
+// applyConfig loads the config file named on the command line, if any, and
+// assigns every pending config file value.
+func (p *Parser) applyConfig() error {
+	p.configApplied = true
+	if p.configFile != "" {
+		entries, err := readConfigFile(p.configFile)
+		if err != nil {
+			return err
+		}
+		if err := p.checkConfigEntries(entries); err != nil {
+			return err
+		}
+		p.configEntries = append(p.configEntries, entries...)
+	}
+	entries := p.configEntries
+	p.configEntries = nil
+	return p.applyConfigEntries(entries)
+}
+
+// applyConfigEntries assigns config file values to their flags.  Flags that
+// already hold a value when this is called are left untouched so that the
+// command line and environment keep precedence.  Repeated keys are all
+// applied, which fills slice flags.  Sections of subcommands that were not
+// used are skipped, like environment variables are.
+func (p *Parser) applyConfigEntries(entries []configEntry) error {
+	alreadySet := make(map[*Flag]bool)
+	for _, e := range entries {
+		sc, f := p.findConfigFlag(e)
+		if f == nil || (sc != &p.Subcommand && !sc.Used) {
+			continue
+		}
+		if _, seen := alreadySet[f]; !seen {
+			alreadySet[f] = f.Changed()
+		}
+		if alreadySet[f] {
+			continue
+		}
+		key := f.LongName
+		if key == "" {
+			key = f.ShortName
+		}
+		debugPrint("setting flag", key, "from config file", e.location())
+		if _, err := sc.setValueForKey(key, e.Value, SourceConfigFile); err != nil {
+			return fmt.Errorf("%s: unable to set flag %s: %w", e.location(), key, valueError(key, e.Value, -1, err))
+		}
+	}
+	return nil
+}
+
+// checkConfigEntries reports every entry that does not match a known
+// subcommand and flag.
+func (p *Parser) checkConfigEntries(entries []configEntry) error {
+	var errs []error
+	for _, e := range entries {
+		if _, f := p.findConfigFlag(e); f != nil {
+			continue
+		}
+		if len(e.Section) > 0 {
+			errs = append(errs, fmt.Errorf("%s: unknown key %q in section [%s]", e.location(), e.Key, strings.Join(e.Section, ".")))
+			continue
+		}
+		errs = append(errs, fmt.Errorf("%s: unknown key %q", e.location(), e.Key))
+	}
+	return errors.Join(errs...)
+}
+
+// findConfigFlag finds the subcommand and flag a config entry refers to.
+// Keys match flag names directly, or case-insensitively once normalized
+// like environment variable names, so MAX_WAIT matches --max-wait.
+func (p *Parser) findConfigFlag(e configEntry) (*Subcommand, *Flag) {
+	sc := &p.Subcommand
+	for _, name := range e.Section {
+		var next *Subcommand
+		for _, child := range sc.Subcommands {
+			if child.HasName(name) {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return nil, nil
+		}
+		sc = next
+	}
+
+	normalizedKey := normalizeEnvVarName(e.Key)
+	for _, f := range sc.Flags {
+		if f.HasName(e.Key) {
+			return sc, f
+		}
+	}
+	for _, f := range sc.Flags {
+		if (f.LongName != "" && normalizeEnvVarName(f.LongName) == normalizedKey) ||
+			(f.ShortName != "" && normalizeEnvVarName(f.ShortName) == normalizedKey) {
+			return sc, f
+		}
+	}
+	return nil, nil
+}
+
+// readConfigFile reads the config file at the supplied path, choosing the
+// format from the file extension.
+func readConfigFile(path string) ([]configEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		return parseJSONConfig(path, data)
+	}
+	return parseINIConfig(path, data)
+}
+
+// parseINIConfig parses INI and dotenv style files.  Blank lines and lines
+// starting with # or ; are ignored, an optional leading "export " is
+// dropped, and values may be wrapped in single or double quotes.
+func parseINIConfig(file string, data []byte) ([]configEntry, error) {
+	var entries []configEntry
+	var section []string
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return nil, fmt.Errorf("%s:%d: malformed section header %q", file, lineNumber, line)
+			}
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			section = nil
+			if name != "" {
+				section = strings.Split(name, ".")
+			}
+			continue
+		}
+
+		line = strings.TrimPrefix(line, "export ")
+		key, value, found := strings.Cut(line, "=")
+		if !found {
+			return nil, fmt.Errorf("%s:%d: expected key=value but found %q", file, lineNumber, line)
+		}
+		key = strings.TrimSpace(key)
+		value = unquoteConfigValue(strings.TrimSpace(value))
+		if key == "" {
+			return nil, fmt.Errorf("%s:%d: missing key before '='", file, lineNumber)
+		}
+		entries = append(entries, configEntry{
+			File:    file,
+			Line:    lineNumber,
+			Section: section,
+			Key:     key,
+			Value:   value,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// unquoteConfigValue removes matching quotes from around a config value.
+// Double quoted values have their escape sequences interpreted.
+func unquoteConfigValue(value string) string {
+	if len(value) < 2 {
+		return value
+	}
+	switch {
+	case value[0] == '"' && value[len(value)-1] == '"':
+		if unquoted, err := strconv.Unquote(value); err == nil {
+			return unquoted
+		}
+		return value[1 : len(value)-1]
+	case value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1]
+	}
+	return value
+}
+
+// parseJSONConfig parses a JSON object into config entries.  Nested objects
+// are sections for subcommands and arrays supply one value per element.
+func parseJSONConfig(file string, data []byte) ([]configEntry, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	lineAt := func(offset int64) int {
+		return bytes.Count(data[:offset], []byte("\n")) + 1
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", file, err)
+	}
+	if delim, ok := tok.(json.Delim); !ok || delim != '{' {
+		return nil, fmt.Errorf("%s:%d: expected a JSON object at the top level", file, lineAt(dec.InputOffset()))
+	}
+
+	var entries []configEntry
+	var walkObject func(section []string) error
+	walkObject = func(section []string) error {
+		for dec.More() {
+			tok, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("%s:%d: %w", file, lineAt(dec.InputOffset()), err)
+			}
+			key := tok.(string)
+			line := lineAt(dec.InputOffset())
+
+			tok, err = dec.Token()
+			if err != nil {
+				return fmt.Errorf("%s:%d: %w", file, lineAt(dec.InputOffset()), err)
+			}
+			switch v := tok.(type) {
+			case json.Delim:
+				switch v {
+				case '{':
+					if err := walkObject(appendPath(section, key)); err != nil {
+						return err
+					}
+				case '[':
+					for dec.More() {
+						tok, err := dec.Token()
+						if err != nil {
+							return fmt.Errorf("%s:%d: %w", file, lineAt(dec.InputOffset()), err)
+						}
+						value, ok := jsonScalarToString(tok)
+						if !ok {
+							return fmt.Errorf("%s:%d: arrays for key %q may only hold strings, numbers, and bools", file, lineAt(dec.InputOffset()), key)
+						}
+						entries = append(entries, configEntry{File: file, Line: line, Section: section, Key: key, Value: value})
+					}
+					if _, err := dec.Token(); err != nil {
+						return fmt.Errorf("%s:%d: %w", file, lineAt(dec.InputOffset()), err)
+					}
+				}
+			case nil:
+				// null leaves the flag at its default
+			default:
+				value, _ := jsonScalarToString(v)
+				entries = append(entries, configEntry{File: file, Line: line, Section: section, Key: key, Value: value})
+			}
+		}
+		// consume the closing brace
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("%s:%d: %w", file, lineAt(dec.InputOffset()), err)
+		}
+		return nil
+	}
+
+	if err := walkObject(nil); err != nil {
+		return nil, err
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, fmt.Errorf("%s:%d: unexpected data after the top level object", file, lineAt(dec.InputOffset()))
+	}
+	return entries, nil
+}
+
+// jsonScalarToString converts a JSON string, number, or bool token to the
+// string form flaggy parses flag values from.
+func jsonScalarToString(tok json.Token) (string, bool) {
+	switch v := tok.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	}
+	return "", false
+}
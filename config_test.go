@@ -0,0 +1,208 @@
+package flaggy_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/integrii/flaggy"
+)
+
+// writeConfigFile writes the supplied contents to a file in a temporary
+// directory and returns its path.
+func writeConfigFile(t *testing.T, name string, contents string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatalf("write config: %v", err)
+	}
+	return path
+}
+
+func TestLoadConfigFileJSON(t *testing.T) {
+	path := writeConfigFile(t, "app.json", `{
+  "region": "eu-west-1",
+  "tag": ["blue", "green"],
+  "deploy": {
+    "canary": {
+      "max-wait": "45s"
+    }
+  }
+}`)
+	p := flaggy.NewParser("app")
+	region := "us-east-1"
+	var tags []string
+	var maxWait time.Duration
+	p.String(&region, "r", "region", "deployment region")
+	p.StringSlice(&tags, "t", "tag", "tags to apply")
+	deploy := flaggy.NewSubcommand("deploy")
+	canary := flaggy.NewSubcommand("canary")
+	canary.Duration(&maxWait, "", "max-wait", "maximum wait")
+	deploy.AttachSubcommand(canary, 1)
+	p.AttachSubcommand(deploy, 1)
+	if err := p.LoadConfigFile(path); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if err := p.ParseArgs([]string{"deploy", "canary"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if region != "eu-west-1" {
+		t.Fatalf("expected region from config, got %q", region)
+	}
+	if strings.Join(tags, ",") != "blue,green" {
+		t.Fatalf("expected tags from config, got %v", tags)
+	}
+	if maxWait != 45*time.Second {
+		t.Fatalf("expected max-wait from config section, got %v", maxWait)
+	}
+}
+
+func TestLoadConfigFileINI(t *testing.T) {
+	path := writeConfigFile(t, "app.ini", `# root settings
+region = "ap-south-1"
+tag = red
+
+[deploy.canary]
+MAX_WAIT = 2m
+`)
+	p := flaggy.NewParser("app")
+	region := "us-east-1"
+	var tags []string
+	var maxWait time.Duration
+	p.String(&region, "r", "region", "deployment region")
+	p.StringSlice(&tags, "t", "tag", "tags to apply")
+	deploy := flaggy.NewSubcommand("deploy")
+	canary := flaggy.NewSubcommand("canary")
+	canary.Duration(&maxWait, "", "max-wait", "maximum wait")
+	deploy.AttachSubcommand(canary, 1)
+	p.AttachSubcommand(deploy, 1)
+	if err := p.LoadConfigFile(path); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if err := p.ParseArgs([]string{"deploy", "canary"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if region != "ap-south-1" || strings.Join(tags, ",") != "red" || maxWait != 2*time.Minute {
+		t.Fatalf("unexpected values: region=%q tags=%v maxWait=%v", region, tags, maxWait)
+	}
+}
+
+func TestConfigFileSkipsUnusedSubcommands(t *testing.T) {
+	path := writeConfigFile(t, "app.ini", "region = from-file\n\n[deploy.canary]\nmax-wait = 2m\n")
+	p := flaggy.NewParser("app")
+	var region string
+	var maxWait time.Duration
+	p.String(&region, "r", "region", "deployment region")
+	deploy := flaggy.NewSubcommand("deploy")
+	canary := flaggy.NewSubcommand("canary")
+	canary.Duration(&maxWait, "", "max-wait", "maximum wait")
+	deploy.AttachSubcommand(canary, 1)
+	p.AttachSubcommand(deploy, 1)
+	if err := p.LoadConfigFile(path); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if err := p.ParseArgs([]string{"deploy"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if region != "from-file" {
+		t.Fatalf("expected region from config, got %q", region)
+	}
+	if maxWait != 0 {
+		t.Fatalf("expected unused canary section to be skipped, got %v", maxWait)
+	}
+}
+
+func TestLoadConfigFileDotenv(t *testing.T) {
+	path := writeConfigFile(t, ".env", "export REGION=sa-east-1\n")
+	p := flaggy.NewParser("app")
+	region := "us-east-1"
+	p.String(&region, "r", "region", "deployment region")
+	if err := p.LoadConfigFile(path); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if err := p.ParseArgs([]string{}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if region != "sa-east-1" {
+		t.Fatalf("expected region from dotenv file, got %q", region)
+	}
+}
+
+func TestConfigFilePrecedence(t *testing.T) {
+	path := writeConfigFile(t, "app.ini", "region = from-file\ntag = file-tag\n")
+	t.Setenv("APP_TAG", "env-tag")
+	p := flaggy.NewParser("app")
+	var region string
+	var tags []string
+	p.String(&region, "r", "region", "deployment region")
+	p.StringSlice(&tags, "t", "tag", "tags to apply")
+	p.Flags[len(p.Flags)-1].EnvVar = "APP_TAG"
+	if err := p.LoadConfigFile(path); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if err := p.ParseArgs([]string{"--region", "from-cli"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if region != "from-cli" {
+		t.Fatalf("expected command line to beat config file, got %q", region)
+	}
+	if strings.Join(tags, ",") != "env-tag" {
+		t.Fatalf("expected environment to beat config file, got %v", tags)
+	}
+}
+
+func TestConfigFlag(t *testing.T) {
+	path := writeConfigFile(t, "app.json", `{"region": "from-flag-file"}`)
+	p := flaggy.NewParser("app")
+	var region string
+	p.String(&region, "r", "region", "deployment region")
+	p.ConfigFlag = "config"
+	if err := p.ParseArgs([]string{"--config", path}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if region != "from-flag-file" {
+		t.Fatalf("expected region from --config file, got %q", region)
+	}
+}
+
+func TestLoadConfigFileAfterParse(t *testing.T) {
+	path := writeConfigFile(t, "app.ini", "region = late\n")
+	p := flaggy.NewParser("app")
+	var region string
+	p.String(&region, "r", "region", "deployment region")
+	if err := p.ParseArgs([]string{}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if err := p.LoadConfigFile(path); err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if region != "late" {
+		t.Fatalf("expected region from config loaded after parse, got %q", region)
+	}
+}
+
+func TestLoadConfigFileUnknownKeys(t *testing.T) {
+	var region string
+	p := flaggy.NewParser("app")
+	p.String(&region, "r", "region", "deployment region")
+	p.AttachSubcommand(flaggy.NewSubcommand("deploy"), 1)
+	ini := writeConfigFile(t, "app.ini", "region = x\n\n[deploy]\nbogus = 1\n")
+	err := p.LoadConfigFile(ini)
+	if err == nil {
+		t.Fatal("expected unknown key error")
+	}
+	if !strings.Contains(err.Error(), "app.ini:4") || !strings.Contains(err.Error(), "bogus") {
+		t.Fatalf("expected error with file and line, got %v", err)
+	}
+
+	js := writeConfigFile(t, "app.json", "{\n  \"region\": \"x\",\n  \"nope\": true\n}")
+	err = p.LoadConfigFile(js)
+	if err == nil {
+		t.Fatal("expected unknown key error")
+	}
+	if !strings.Contains(err.Error(), "app.json:3") || !strings.Contains(err.Error(), "nope") {
+		t.Fatalf("expected error with file and line, got %v", err)
+	}
+}
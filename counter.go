@@ -0,0 +1,93 @@
+package flaggy
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Counter is a flag value that counts how many times its flag is used, like
+// -v -v -v or -vvv for increasing verbosity.  Supplying a value, as in
+// --verbose=3, sets the count directly.  Counters are added with Count.
+type Counter int
+
+// Set increments the counter when the flag is used without a value and sets
+// it when a number is supplied.
+func (c *Counter) Set(value string) error {
+	if value == "true" {
+		*c++
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	if n < 0 {
+		return errors.New("count can not be negative: " + value)
+	}
+	*c = Counter(n)
+	return nil
+}
+
+// String returns the current count.
+func (c *Counter) String() string {
+	return strconv.Itoa(int(*c))
+}
+
+// Type returns the name of the counter type for help output.
+func (c *Counter) Type() string {
+	return "count"
+}
+
+// IsBoolFlag lets the counter be used without a following value.
+func (c *Counter) IsBoolFlag() bool {
+	return true
+}
+
+// isCounter reports whether the flag counts its occurrences.
+func (f *Flag) isCounter() bool {
+	_, ok := f.AssignmentVar.(*Counter)
+	return ok
+}
+
+// expandCounterClusters splits arguments like -vvv into -v -v -v when v is
+// the short name of a counter flag.  This is done even when POSIXShortFlags
+// is disabled, as repeated letters can not be mistaken for another flag.
+// Values of flags that take one are passed through untouched.
+func (p *Parser) expandCounterClusters(args []string) []string {
+	expanded := make([]string, 0, len(args))
+	var skipNext bool
+	for i, a := range args {
+		if skipNext {
+			skipNext = false
+			expanded = append(expanded, a)
+			continue
+		}
+		if a == "--" {
+			return append(expanded, args[i:]...)
+		}
+		name := parseFlagToName(a)
+		if !strings.HasPrefix(a, "-") || strings.Contains(a, "=") || name == "" {
+			expanded = append(expanded, a)
+			continue
+		}
+
+		if f := p.findFlagInTree(name); f != nil {
+			if !f.isBool() {
+				skipNext = true
+			}
+			expanded = append(expanded, a)
+			continue
+		}
+		letter := string([]rune(name)[0])
+		f := p.findShortFlagInTree(letter)
+		if strings.HasPrefix(a, "--") || f == nil || !f.isCounter() || strings.Trim(name, letter) != "" {
+			expanded = append(expanded, a)
+			continue
+		}
+		for range name {
+			expanded = append(expanded, "-"+letter)
+		}
+	}
+	return expanded
+}
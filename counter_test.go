@@ -0,0 +1,77 @@
+package flaggy_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		name  string
+		posix bool
+		args  []string
+		want  int
+	}{
+		{name: "unused", args: []string{}, want: 0},
+		{name: "repeated", args: []string{"-v", "-v", "--verbose"}, want: 3},
+		{name: "clustered", args: []string{"-vvv"}, want: 3},
+		{name: "clustered posix", posix: true, args: []string{"-vvq"}, want: 2},
+		{name: "explicit", args: []string{"--verbose=3"}, want: 3},
+		{name: "explicit then repeated", args: []string{"-v=2", "-v"}, want: 3},
+		{name: "does not consume the next argument", args: []string{"-v", "file.txt"}, want: 1},
+		{name: "cluster as a value", args: []string{"-o", "-vv", "-v"}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := flaggy.NewParser("app")
+			p.ErrorHandling = flaggy.ContinueOnError
+			p.POSIXShortFlags = tt.posix
+			var verbosity int
+			var quiet bool
+			var output, file string
+			p.Count(&verbosity, "v", "verbose", "Increase verbosity")
+			p.Bool(&quiet, "q", "quiet", "Quiet output")
+			p.String(&output, "o", "output", "Output file")
+			p.AddPositionalValue(&file, "file", 1, false, "Input file")
+			if err := p.ParseArgs(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if verbosity != tt.want {
+				t.Fatalf("expected verbosity %d, got %d", tt.want, verbosity)
+			}
+		})
+	}
+}
+
+func TestCountInvalidValue(t *testing.T) {
+	for _, args := range [][]string{{"--verbose=lots"}, {"--verbose=-1"}} {
+		p := flaggy.NewParser("app")
+		p.ErrorHandling = flaggy.ContinueOnError
+		var verbosity int
+		p.Count(&verbosity, "v", "verbose", "Increase verbosity")
+		err := p.ParseArgs(args)
+		var conversion *flaggy.ConversionError
+		if !errors.As(err, &conversion) {
+			t.Fatalf("expected *ConversionError for %v, got %T: %v", args, err, err)
+		}
+	}
+}
+
+func TestCountShownAsRepeatable(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var verbosity int
+	p.Count(&verbosity, "v", "verbose", "Increase verbosity")
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	out := strings.Join(h.Lines, "\n")
+	if !strings.Contains(out, "Increase verbosity (repeatable)") {
+		t.Fatalf("expected counter to be shown as repeatable:\n%s", out)
+	}
+	if strings.Contains(out, "(default: 0)") {
+		t.Fatalf("expected no zero default for counter:\n%s", out)
+	}
+}
@@ -0,0 +1,76 @@
+package flaggy
+
+import (
+	"encoding/csv"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"unicode/utf8"
+)
+
+// sliceValue is implemented by custom values that append each assignment to
+// a slice, so that they can be split on a delimiter and reset.
+type sliceValue interface {
+	reset()
+}
+
+// isSlice reports whether each value assigned to the flag is appended to a
+// slice.
+func (f *Flag) isSlice() bool {
+	if handler, ok := lookupType(f.AssignmentVar); ok {
+		return handler.slice
+	}
+	_, ok := f.AssignmentVar.(sliceValue)
+	return ok
+}
+
+// resetSlice empties the slice behind the assignment variable.  A new slice
+// is used so that the backing array of the default is not overwritten.
+func resetSlice(assignmentVar interface{}) {
+	if sv, ok := assignmentVar.(sliceValue); ok {
+		sv.reset()
+		return
+	}
+	v := reflect.ValueOf(assignmentVar).Elem()
+	v.Set(reflect.Zero(v.Type()))
+}
+
+// splitSliceValue splits a slice flag's value on the delimiter.  Elements
+// may be quoted like CSV fields, so "a,b",c holds the two elements a,b and c.
+// Quotes inside an element that is not quoted are kept as they are.
+func splitSliceValue(value string, delimiter string) ([]string, error) {
+	d, size := utf8.DecodeRuneInString(delimiter)
+	if size != len(delimiter) || d == '"' || d == '\r' || d == '\n' || d == utf8.RuneError {
+		return nil, errors.New("invalid slice delimiter " + delimiter)
+	}
+	r := csv.NewReader(strings.NewReader(value))
+	r.Comma = d
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+	fields, err := r.Read()
+	if err == io.EOF {
+		return []string{""}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// resolveSliceOptions passes the parser's SliceDelimiter and
+// ReplaceSliceDefaults on to the flags of every subcommand.  A flag's own
+// Delimiter takes precedence.
+func (p *Parser) resolveSliceOptions() {
+	var walk func(sc *Subcommand)
+	walk = func(sc *Subcommand) {
+		for _, f := range sc.Flags {
+			f.delimiter = p.SliceDelimiter
+			f.replaceDefault = p.ReplaceSliceDefaults
+		}
+		for _, child := range sc.Subcommands {
+			walk(child)
+		}
+	}
+	walk(&p.Subcommand)
+}
@@ -0,0 +1,118 @@
+package flaggy_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestSliceDelimiter(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var ports []int
+	var names []string
+	var plain []string
+	p.IntSlice(&ports, "p", "ports", "Ports").Delimiter = ","
+	p.StringSlice(&names, "n", "names", "Names").Delimiter = ";"
+	p.StringSlice(&plain, "", "plain", "Not split")
+	args := []string{"--ports", "80,443", "-p=8080", "-n", `a;"b;c";d`, "--plain", "x,y"}
+	if err := p.ParseArgs(args); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(ports, []int{80, 443, 8080}) {
+		t.Fatalf("unexpected ports %v", ports)
+	}
+	if !reflect.DeepEqual(names, []string{"a", "b;c", "d"}) {
+		t.Fatalf("unexpected names %v", names)
+	}
+	if !reflect.DeepEqual(plain, []string{"x,y"}) {
+		t.Fatalf("expected slices without a delimiter to be left whole, got %v", plain)
+	}
+}
+
+func TestSliceDelimiterInnerQuotes(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var messages []string
+	p.StringSlice(&messages, "m", "msg", "Messages").Delimiter = ","
+	if err := p.ParseArgs([]string{"--msg", `say "hi",bye`}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(messages, []string{`say "hi"`, "bye"}) {
+		t.Fatalf("unexpected messages %v", messages)
+	}
+}
+
+func TestParserSliceDelimiter(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.SliceDelimiter = ","
+	var tags []string
+	var levels []string
+	var name string
+	sub := flaggy.NewSubcommand("build")
+	sub.StringSlice(&tags, "t", "tag", "Tags")
+	sub.EnumSlice(&levels, "l", "level", "Levels", []string{"low", "high"})
+	sub.String(&name, "n", "name", "Name")
+	p.AttachSubcommand(sub, 1)
+	if err := p.ParseArgs([]string{"build", "-t", `one,"two,three"`, "-l", "low,high", "-n", "a,b"}); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(tags, []string{"one", "two,three"}) {
+		t.Fatalf("unexpected tags %v", tags)
+	}
+	if !reflect.DeepEqual(levels, []string{"low", "high"}) {
+		t.Fatalf("unexpected levels %v", levels)
+	}
+	if name != "a,b" {
+		t.Fatalf("expected non-slice flags to be left whole, got %q", name)
+	}
+}
+
+func TestSliceDelimiterConversionError(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var ports []int
+	p.IntSlice(&ports, "p", "ports", "Ports").Delimiter = ","
+	err := p.ParseArgs([]string{"--ports", "80,http"})
+	var conversion *flaggy.ConversionError
+	if !errors.As(err, &conversion) {
+		t.Fatalf("expected *ConversionError, got %T: %v", err, err)
+	}
+}
+
+func TestReplaceDefault(t *testing.T) {
+	tests := []struct {
+		name     string
+		replace  bool
+		parser   bool
+		args     []string
+		expected []string
+	}{
+		{name: "append", args: []string{"-t", "b"}, expected: []string{"a", "b"}},
+		{name: "replace", replace: true, args: []string{"-t", "b", "-t", "c"}, expected: []string{"b", "c"}},
+		{name: "replace from parser", parser: true, args: []string{"-t", "b"}, expected: []string{"b"}},
+		{name: "default kept when unused", replace: true, args: []string{}, expected: []string{"a"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := flaggy.NewParser("app")
+			p.ErrorHandling = flaggy.ContinueOnError
+			p.ReplaceSliceDefaults = tt.parser
+			defaults := []string{"a", "z"}
+			tags := defaults[:1]
+			p.StringSlice(&tags, "t", "tag", "Tags").ReplaceDefault = tt.replace
+			if err := p.ParseArgs(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(tags, tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, tags)
+			}
+			if (tt.replace || tt.parser) && defaults[1] != "z" {
+				t.Fatalf("expected the default's backing array to be left alone, got %v", defaults)
+			}
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package flaggy
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// HasName indicates that this subcommand's name, short name, or one of its
+// aliases matches the supplied name.
+func (sc *Subcommand) HasName(name string) bool {
+	if sc.Name == name || (sc.ShortName != "" && sc.ShortName == name) {
+		return true
+	}
+	for _, alias := range sc.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// conflictingFlagAlias returns the first alias of a flag that is also a
+// name or alias of another flag in the list, or an empty string when there
+// is none.
+func conflictingFlagAlias(flags []*Flag) string {
+	for i, f := range flags {
+		for _, alias := range f.Aliases {
+			for j, other := range flags {
+				if i != j && other.HasName(alias) {
+					return alias
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// conflictingSubcommandAlias returns the first alias of either subcommand
+// that also names the other, or an empty string when there is none.
+func conflictingSubcommandAlias(a *Subcommand, b *Subcommand) string {
+	for _, alias := range a.Aliases {
+		if b.HasName(alias) {
+			return alias
+		}
+	}
+	for _, alias := range b.Aliases {
+		if a.HasName(alias) {
+			return alias
+		}
+	}
+	return ""
+}
+
+// hidden reports whether the subcommand should be left out of help,
+// completion, and suggestions.  Deprecated subcommands are hidden.
+func (sc *Subcommand) hidden() bool {
+	return sc.Hidden || sc.Deprecated != ""
+}
+
+// warningWriter returns where deprecation warnings are written.
+func (p *Parser) warningWriter() io.Writer {
+	if p.WarningWriter != nil {
+		return p.WarningWriter
+	}
+	return os.Stderr
+}
+
+// warnDeprecated writes a warning for every deprecated subcommand that was
+// used and every deprecated flag of the root parser or a used subcommand
+// that was given a value while parsing.
+func (p *Parser) warnDeprecated() {
+	w := p.warningWriter()
+	path := p.usedSubcommandPath()
+	for _, sc := range path {
+		if sc.Deprecated != "" {
+			fmt.Fprintln(w, "Warning: subcommand "+sc.Name+" is deprecated: "+sc.Deprecated)
+		}
+	}
+	for _, sc := range append([]*Subcommand{&p.Subcommand}, path...) {
+		for _, f := range sc.Flags {
+			if f.Deprecated != "" && f.Changed() && f.Source != SourceProgrammatic {
+				fmt.Fprintln(w, "Warning: flag "+f.displayName()+" is deprecated: "+f.Deprecated)
+			}
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package flaggy_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestDeprecatedFlagWarns(t *testing.T) {
+	var listen string
+	var warnings bytes.Buffer
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.WarningWriter = &warnings
+	p.String(&listen, "l", "listen", "Address to listen on")
+	p.String(&listen, "", "addr", "Address to listen on").Deprecated = "use --listen instead"
+	if err := p.ParseArgs([]string{"--addr", ":8080"}); err != nil {
+		t.Fatal(err)
+	}
+	if listen != ":8080" {
+		t.Fatalf("expected the deprecated flag to still parse, got %q", listen)
+	}
+	if warnings.String() != "Warning: flag --addr is deprecated: use --listen instead\n" {
+		t.Fatalf("unexpected warnings: %q", warnings.String())
+	}
+}
+
+func TestDeprecatedSubcommandWarns(t *testing.T) {
+	var listen string
+	var warnings bytes.Buffer
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.WarningWriter = &warnings
+	p.String(&listen, "l", "listen", "Address to listen on")
+	serve := flaggy.NewSubcommand("serve")
+	serve.Deprecated = "use run instead"
+	p.AttachSubcommand(serve, 1)
+	if err := p.ParseArgs([]string{"serve", "-l", ":80"}); err != nil {
+		t.Fatal(err)
+	}
+	if warnings.String() != "Warning: subcommand serve is deprecated: use run instead\n" {
+		t.Fatalf("unexpected warnings: %q", warnings.String())
+	}
+}
+
+func TestNoWarningsForUnusedSubcommands(t *testing.T) {
+	var warnings bytes.Buffer
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.WarningWriter = &warnings
+	var old string
+	legacy := flaggy.NewSubcommand("legacy")
+	f := legacy.String(&old, "", "old", "old setting")
+	f.Deprecated = "use --new instead"
+	f.Source = flaggy.SourceEnvironment
+	p.AttachSubcommand(legacy, 1)
+	p.AttachSubcommand(flaggy.NewSubcommand("run"), 1)
+	if err := p.ParseArgs([]string{"run"}); err != nil {
+		t.Fatal(err)
+	}
+	if warnings.Len() != 0 {
+		t.Fatalf("expected no warnings for an unused subcommand, got %q", warnings.String())
+	}
+}
+
+func TestNoWarningsWithoutDeprecatedNames(t *testing.T) {
+	var listen string
+	var warnings bytes.Buffer
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.WarningWriter = &warnings
+	p.String(&listen, "l", "listen", "Address to listen on")
+	p.String(&listen, "", "addr", "Address to listen on").Deprecated = "use --listen instead"
+	p.AttachSubcommand(flaggy.NewSubcommand("run"), 1)
+	serve := flaggy.NewSubcommand("serve")
+	serve.Deprecated = "use run instead"
+	p.AttachSubcommand(serve, 1)
+	if err := p.ParseArgs([]string{"run", "--listen", ":80"}); err != nil {
+		t.Fatal(err)
+	}
+	if warnings.Len() != 0 {
+		t.Fatalf("expected no warnings, got %q", warnings.String())
+	}
+}
+
+func TestDeprecatedHiddenFromHelpAndCompletion(t *testing.T) {
+	outputs := make(map[string]string)
+	for _, args := range [][]string{nil, {""}, {"run", "-"}} {
+		var listen string
+		p := flaggy.NewParser("app")
+		p.String(&listen, "l", "listen", "Address to listen on")
+		p.String(&listen, "", "addr", "Address to listen on").Deprecated = "use --listen instead"
+		p.AttachSubcommand(flaggy.NewSubcommand("run"), 1)
+		serve := flaggy.NewSubcommand("serve")
+		serve.Deprecated = "use run instead"
+		p.AttachSubcommand(serve, 1)
+		if args == nil {
+			var h flaggy.Help
+			h.ExtractValues(p, "")
+			outputs["help"] = strings.Join(h.Lines, "\n")
+			continue
+		}
+		outputs["completion"] += strings.Join(completeWords(t, p, args...), "\n") + "\n"
+	}
+	for name, out := range outputs {
+		if strings.Contains(out, "addr") || strings.Contains(out, "serve") {
+			t.Fatalf("expected %s output to hide deprecated names:\n%s", name, out)
+		}
+		if !strings.Contains(out, "listen") {
+			t.Fatalf("expected %s output to contain the new flag:\n%s", name, out)
+		}
+	}
+}
+
+func TestFlagAliases(t *testing.T) {
+	for _, arg := range []string{"--region", "--zone", "-location"} {
+		p := flaggy.NewParser("app")
+		p.ErrorHandling = flaggy.ContinueOnError
+		var region string
+		p.String(&region, "r", "region", "Region").Aliases = []string{"zone", "location"}
+		if err := p.ParseArgs([]string{arg, "eu"}); err != nil {
+			t.Fatalf("%s: %v", arg, err)
+		}
+		if region != "eu" || !p.Changed("zone") {
+			t.Fatalf("%s: expected region to be set through the alias, got %q", arg, region)
+		}
+	}
+}
+
+func TestSubcommandAliases(t *testing.T) {
+	for _, name := range []string{"run", "start", "up"} {
+		p := flaggy.NewParser("app")
+		p.ErrorHandling = flaggy.ContinueOnError
+		run := flaggy.NewSubcommand("run")
+		run.Aliases = []string{"start", "up"}
+		p.AttachSubcommand(run, 1)
+		if err := p.ParseArgs([]string{name}); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		if !p.Subcommands[0].Used {
+			t.Fatalf("%s: expected the run subcommand to be used", name)
+		}
+	}
+}
+
+func TestFlagAliasConflicts(t *testing.T) {
+	p := flaggy.NewParser("app")
+	var region, zone string
+	p.String(&region, "r", "region", "Region").Aliases = []string{"zone"}
+	func() {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Fatal("expected a flag named like an existing alias to panic")
+			}
+		}()
+		p.String(&zone, "", "zone", "Zone")
+	}()
+
+	p = flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.String(&region, "r", "region", "Region").Aliases = []string{"area"}
+	p.String(&zone, "z", "zone", "Zone").Aliases = []string{"area"}
+	err := p.ParseArgs([]string{})
+	var conflict *flaggy.AliasConflictError
+	if !errors.As(err, &conflict) || conflict.Alias != "area" {
+		t.Fatalf("expected *AliasConflictError for the shared alias, got %T: %v", err, err)
+	}
+}
+
+func TestSubcommandAliasConflicts(t *testing.T) {
+	p := flaggy.NewParser("app")
+	run := flaggy.NewSubcommand("run")
+	run.Aliases = []string{"start"}
+	p.AttachSubcommand(run, 1)
+	start := flaggy.NewSubcommand("start")
+	defer func() {
+		if r := recover(); r == nil {
+			t.Fatal("expected a subcommand named like an existing alias to panic")
+		}
+	}()
+	p.AttachSubcommand(start, 1)
+}
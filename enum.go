@@ -0,0 +1,105 @@
+package flaggy
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// choiceValue is implemented by values that only accept a fixed set of
+// choices, which are shown in help and offered by shell completion.
+type choiceValue interface {
+	choices() []string
+}
+
+// enumValue restricts a string flag to a set of allowed values.
+type enumValue struct {
+	value   *string
+	allowed []string
+}
+
+// Set stores the value when it is one of the allowed choices.
+func (e *enumValue) Set(value string) error {
+	if err := checkChoice(value, e.allowed); err != nil {
+		return err
+	}
+	*e.value = value
+	return nil
+}
+
+// String returns the current value.
+func (e *enumValue) String() string {
+	return *e.value
+}
+
+// Type returns the name of the enum type for help output.
+func (e *enumValue) Type() string {
+	return "enum"
+}
+
+func (e *enumValue) choices() []string {
+	return e.allowed
+}
+
+// enumSliceValue restricts the values appended to a string slice flag to a
+// set of allowed values.
+type enumSliceValue struct {
+	values  *[]string
+	allowed []string
+}
+
+// Set appends the value when it is one of the allowed choices.
+func (e *enumSliceValue) Set(value string) error {
+	if err := checkChoice(value, e.allowed); err != nil {
+		return err
+	}
+	*e.values = append(*e.values, value)
+	return nil
+}
+
+// String returns the current values joined by commas.
+func (e *enumSliceValue) String() string {
+	return strings.Join(*e.values, ",")
+}
+
+// Type returns the name of the enum slice type for help output.
+func (e *enumSliceValue) Type() string {
+	return "enumSlice"
+}
+
+func (e *enumSliceValue) choices() []string {
+	return e.allowed
+}
+
+func (e *enumSliceValue) reset() {
+	*e.values = nil
+}
+
+// checkChoice returns an error listing the allowed choices when the value is
+// not one of them.
+func checkChoice(value string, allowed []string) error {
+	if slices.Contains(allowed, value) {
+		return nil
+	}
+	return &choiceError{Value: value, Choices: allowed}
+}
+
+// choiceError is returned when an enum flag is given a value that is not
+// one of its choices.
+type choiceError struct {
+	Value   string
+	Choices []string
+}
+
+func (e *choiceError) Error() string {
+	return "invalid value " + strconv.Quote(e.Value) + ", must be one of: " + strings.Join(e.Choices, ", ")
+}
+
+// choices returns the allowed values of an enum flag, or nil when the flag
+// accepts any value.
+func (f *Flag) choices() []string {
+	if c, ok := f.AssignmentVar.(choiceValue); ok {
+		return c.choices()
+	}
+	return nil
+}
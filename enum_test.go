@@ -0,0 +1,76 @@
+package flaggy_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestEnum(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	format := "table"
+	var outputs []string
+	p.Enum(&format, "f", "format", "Output format", []string{"json", "yaml", "table"})
+	p.EnumSlice(&outputs, "o", "output", "Outputs", []string{"stdout", "file"})
+	if err := p.ParseArgs([]string{"--format", "yaml", "-o", "file", "-o=stdout"}); err != nil {
+		t.Fatal(err)
+	}
+	if format != "yaml" {
+		t.Fatalf("expected yaml, got %q", format)
+	}
+	if strings.Join(outputs, ",") != "file,stdout" {
+		t.Fatalf("unexpected outputs %v", outputs)
+	}
+}
+
+func TestEnumRejectsUnknownValue(t *testing.T) {
+	tests := [][]string{
+		{"--format=xml"},
+		{"-o", "socket"},
+	}
+	for _, args := range tests {
+		p := flaggy.NewParser("app")
+		p.ErrorHandling = flaggy.ContinueOnError
+		var format string
+		var outputs []string
+		p.Enum(&format, "f", "format", "Output format", []string{"json", "yaml", "table"})
+		p.EnumSlice(&outputs, "o", "output", "Outputs", []string{"stdout", "file"})
+		err := p.ParseArgs(args)
+		var conversion *flaggy.ConversionError
+		if !errors.As(err, &conversion) {
+			t.Fatalf("expected *ConversionError for %v, got %T: %v", args, err, err)
+		}
+		if !strings.Contains(err.Error(), "must be one of:") {
+			t.Fatalf("expected error to list the choices, got %v", err)
+		}
+	}
+}
+
+func TestEnumChoicesInHelp(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	format := "json"
+	p.Enum(&format, "f", "format", "Output format", []string{"json", "yaml", "table"})
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	out := strings.Join(h.Lines, "\n")
+	if !strings.Contains(out, "Output format (default: json) (choices: json, yaml, table)") {
+		t.Fatalf("expected help to list the choices:\n%s", out)
+	}
+}
+
+func TestEnumChoicesInCompletion(t *testing.T) {
+	newParser := func() *flaggy.Parser {
+		p := flaggy.NewParser("app")
+		var format string
+		sub := flaggy.NewSubcommand("render")
+		sub.Enum(&format, "f", "format", "Output format", []string{"json", "yaml", "table"})
+		p.AttachSubcommand(sub, 1)
+		return p
+	}
+	verifyCandidates(t, completeWords(t, newParser(), "render", "--format", ""), "json", "yaml", "table")
+	verifyCandidates(t, completeWords(t, newParser(), "render", "-f=t"), "-f=table")
+}
@@ -0,0 +1,137 @@
+package flaggy
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// SetEnvPrefix sets the prefix used to derive environment variable names
+// for flags on the default parser.
+func SetEnvPrefix(prefix string) {
+	DefaultParser.EnvPrefix = prefix
+}
+
+// envVarName returns the environment variable that can supply a value for
+// the flag.  An explicit Flag.EnvVar always wins.  Otherwise, when an
+// EnvPrefix is configured on the parser, the name is derived from the
+// prefix, the names of the subcommands leading to the flag, and the flag's
+// own name.  An empty string means the flag has no environment binding.
+func (p *Parser) envVarName(path []*Subcommand, f *Flag) string {
+	if f.EnvVar != "" {
+		return f.EnvVar
+	}
+	if p.EnvPrefix == "" {
+		return ""
+	}
+	name := f.LongName
+	if name == "" {
+		name = f.ShortName
+	}
+	parts := []string{p.EnvPrefix}
+	for _, sc := range path {
+		parts = append(parts, sc.Name)
+	}
+	parts = append(parts, name)
+	return normalizeEnvVarName(strings.Join(parts, "_"))
+}
+
+// normalizeEnvVarName upper cases the supplied name and replaces any
+// character that is not valid in an environment variable name with an
+// underscore.
+func normalizeEnvVarName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(name) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' {
+			b.WriteRune(r)
+			continue
+		}
+		b.WriteRune('_')
+	}
+	return b.String()
+}
+
+// usedSubcommandPath returns the chain of subcommands that were used during
+// parsing, not including the root parser itself.
+func (p *Parser) usedSubcommandPath() []*Subcommand {
+	var path []*Subcommand
+	sc := &p.Subcommand
+	for {
+		var next *Subcommand
+		for _, child := range sc.Subcommands {
+			if child.Used {
+				next = child
+				break
+			}
+		}
+		if next == nil {
+			return path
+		}
+		path = append(path, next)
+		sc = next
+	}
+}
+
+// subcommandPath finds the chain of subcommands leading from the root parser
+// to the target subcommand, not including the root parser itself.  Nil is
+// returned when the target is the root parser or cannot be found.
+func (p *Parser) subcommandPath(target *Subcommand) []*Subcommand {
+	var walk func(sc *Subcommand, path []*Subcommand) []*Subcommand
+	walk = func(sc *Subcommand, path []*Subcommand) []*Subcommand {
+		for _, child := range sc.Subcommands {
+			childPath := append(append([]*Subcommand{}, path...), child)
+			if child == target {
+				return childPath
+			}
+			if found := walk(child, childPath); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+	return walk(&p.Subcommand, nil)
+}
+
+// applyEnvironment assigns values from bound environment variables to
+// flags on the root parser and every used subcommand that were not already
+// set on the command line.  This gives command line values precedence over
+// the environment, and the environment precedence over defaults.
+func (p *Parser) applyEnvironment() error {
+	if err := p.applyEnvironmentToSubcommand(&p.Subcommand, nil); err != nil {
+		return err
+	}
+	path := p.usedSubcommandPath()
+	for i, sc := range path {
+		if err := p.applyEnvironmentToSubcommand(sc, path[:i+1]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// applyEnvironmentToSubcommand assigns environment values to the unset
+// flags of a single subcommand.
+func (p *Parser) applyEnvironmentToSubcommand(sc *Subcommand, path []*Subcommand) error {
+	for _, f := range sc.Flags {
+		if f.Changed() {
+			continue
+		}
+		name := p.envVarName(path, f)
+		if name == "" {
+			continue
+		}
+		value, ok := os.LookupEnv(name)
+		if !ok {
+			continue
+		}
+		key := f.LongName
+		if key == "" {
+			key = f.ShortName
+		}
+		debugPrint("setting flag", key, "from environment variable", name)
+		if _, err := sc.setValueForKey(key, value, SourceEnvironment); err != nil {
+			return fmt.Errorf("unable to set flag %s from environment variable %s: %w", key, name, valueError(key, value, -1, err))
+		}
+	}
+	return nil
+}
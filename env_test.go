@@ -0,0 +1,118 @@
+package flaggy_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestEnvVarBinding(t *testing.T) {
+	t.Setenv("APP_PORT", "8080")
+	p := flaggy.NewParser("app")
+	var port int
+	p.Int(&port, "p", "port", "listen port").EnvVar = "APP_PORT"
+	if err := p.ParseArgs([]string{}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if port != 8080 {
+		t.Fatalf("expected port 8080 from env, got %d", port)
+	}
+}
+
+func TestEnvVarCLITakesPrecedence(t *testing.T) {
+	t.Setenv("APP_PORT", "8080")
+	p := flaggy.NewParser("app")
+	port := 80
+	p.Int(&port, "p", "port", "listen port").EnvVar = "APP_PORT"
+	if err := p.ParseArgs([]string{"--port", "9090"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if port != 9090 {
+		t.Fatalf("expected CLI value 9090 to win, got %d", port)
+	}
+}
+
+func TestEnvVarDefaultWhenUnset(t *testing.T) {
+	p := flaggy.NewParser("app")
+	port := 80
+	p.Int(&port, "p", "port", "listen port").EnvVar = "APP_PORT_UNSET_FOR_TEST"
+	if err := p.ParseArgs([]string{}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if port != 80 {
+		t.Fatalf("expected default 80, got %d", port)
+	}
+}
+
+func TestEnvPrefixDerivesNames(t *testing.T) {
+	t.Setenv("MYAPP_DEBUG", "true")
+	t.Setenv("MYAPP_DEPLOY_CANARY_MAX_WAIT", "90s")
+	t.Setenv("MYAPP_DESTROY_FORCE", "true")
+
+	p := flaggy.NewParser("myapp")
+	p.EnvPrefix = "myapp"
+	var debug bool
+	p.Bool(&debug, "d", "debug", "debug output")
+
+	deploy := flaggy.NewSubcommand("deploy")
+	canary := flaggy.NewSubcommand("canary")
+	var maxWait time.Duration
+	canary.Duration(&maxWait, "", "max-wait", "maximum wait")
+	deploy.AttachSubcommand(canary, 1)
+	p.AttachSubcommand(deploy, 1)
+
+	destroy := flaggy.NewSubcommand("destroy")
+	var force bool
+	destroy.Bool(&force, "", "force", "force destroy")
+	p.AttachSubcommand(destroy, 1)
+
+	if err := p.ParseArgs([]string{"deploy", "canary"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if !debug {
+		t.Fatal("expected root debug flag to be set from MYAPP_DEBUG")
+	}
+	if maxWait != 90*time.Second {
+		t.Fatalf("expected max-wait 90s, got %v", maxWait)
+	}
+	if force {
+		t.Fatal("expected flags of unused subcommands to ignore the environment")
+	}
+}
+
+func TestEnvVarConversionError(t *testing.T) {
+	t.Setenv("APP_PORT", "eighty")
+	p := flaggy.NewParser("app")
+	var port int
+	p.Int(&port, "p", "port", "listen port").EnvVar = "APP_PORT"
+	err := p.ParseArgs([]string{})
+	if err == nil {
+		t.Fatal("expected conversion error from environment value")
+	}
+	if !strings.Contains(err.Error(), "APP_PORT") {
+		t.Fatalf("expected error to name the environment variable, got %v", err)
+	}
+}
+
+func TestEnvVarShownInHelp(t *testing.T) {
+	p := flaggy.NewParser("myapp")
+	p.EnvPrefix = "MYAPP"
+	port := 80
+	p.Int(&port, "p", "port", "listen port")
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	var found bool
+	for _, line := range h.Lines {
+		if strings.Contains(line, "--port") {
+			found = true
+			if !strings.Contains(line, "(default: 80) (env: MYAPP_PORT)") {
+				t.Fatalf("expected env var next to default, got %q", line)
+			}
+		}
+	}
+	if !found {
+		t.Fatal("port flag missing from help output")
+	}
+}
@@ -0,0 +1,281 @@
+package flaggy
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrorHandling defines how Parser.ParseArgs behaves when the supplied
+// arguments can not be parsed.
+type ErrorHandling int
+
+const (
+	// ContinueOnError returns a descriptive error from ParseArgs without
+	// printing help or exiting.  Help, version, and completion requests
+	// print their output and return ErrHelp, ErrVersion, or ErrCompletion.
+	ContinueOnError ErrorHandling = iota
+	// ExitOnError shows help with the error message and exits with status
+	// code 2 when the arguments are invalid.  Help and version requests
+	// exit with status code 0.  Errors converting values into flags are
+	// returned.  This is the default for new parsers.
+	ExitOnError
+	// PanicOnError panics with the error instead of returning it, and
+	// panics instead of exiting.
+	PanicOnError
+)
+
+// ErrHelp is returned by ParseArgs when help was requested with -h or --help
+// and the parser does not exit on errors.
+var ErrHelp = errors.New("flaggy: help requested")
+
+// ErrVersion is returned by ParseArgs when the version was requested with
+// --version and the parser does not exit on errors.
+var ErrVersion = errors.New("flaggy: version requested")
+
+// ErrCompletion is returned by ParseArgs when a completion script or
+// completion candidates were written and the parser does not exit on errors.
+var ErrCompletion = errors.New("flaggy: completion requested")
+
+// SetErrorHandling sets how the default parser handles parsing errors.
+func SetErrorHandling(errorHandling ErrorHandling) {
+	DefaultParser.ErrorHandling = errorHandling
+}
+
+// UnknownArgumentError is returned when arguments were supplied that no
+// flag, positional value, or subcommand accepts.
+type UnknownArgumentError struct {
+	Subcommand  string              // the subcommand being parsed when the arguments were found
+	Arguments   []string            // the unexpected arguments
+	Position    int                 // the relative position of an unexpected positional argument, or 0 for flags
+	Subcommands []string            // the subcommands that could have been used at Position
+	Suggestions map[string][]string // likely intended subcommands or flags for each argument
+}
+
+func (e *UnknownArgumentError) Error() string {
+	var msg string
+	switch {
+	case len(e.Subcommands) > 0:
+		msg = e.Subcommand + ": No subcommand or positional value found at position " + strconv.Itoa(e.Position) + ": " + strings.Join(e.Arguments, " ")
+	case e.Position > 0:
+		msg = "Unexpected argument: " + strings.Join(e.Arguments, " ")
+	default:
+		msg = "Unknown arguments supplied: " + strings.Join(e.Arguments, " ")
+	}
+	for _, arg := range e.Arguments {
+		suggestions := e.Suggestions[arg]
+		if len(suggestions) == 0 {
+			continue
+		}
+		if len(e.Arguments) == 1 {
+			msg += "\n" + formatSuggestions(suggestions)
+		} else {
+			msg += "\n" + strings.TrimSuffix(formatSuggestions(suggestions), "?") + " instead of '" + arg + "'?"
+		}
+	}
+	if len(e.Subcommands) > 0 {
+		msg += "\nAvailable subcommands: " + strings.Join(e.Subcommands, " ")
+	}
+	return msg
+}
+
+// MissingValueError is returned when a flag that takes a value was the last
+// argument supplied.
+type MissingValueError struct {
+	Flag string // the name of the flag, as supplied
+}
+
+func (e *MissingValueError) Error() string {
+	return "Expected a following arg for flag " + e.Flag + ", but it did not exist."
+}
+
+// RequiredPositionalError is returned when a required positional value was
+// not supplied.
+type RequiredPositionalError struct {
+	Subcommand string // the subcommand owning the positional, or empty for the root parser
+	Name       string // the name of the positional value
+	Position   int    // the relative position the value was expected at
+}
+
+func (e *RequiredPositionalError) Error() string {
+	if e.Subcommand == "" {
+		return "Required global positional variable " + e.Name + " not found at position " + strconv.Itoa(e.Position)
+	}
+	return "Required positional of subcommand " + e.Subcommand + " named " + e.Name + " not found at position " + strconv.Itoa(e.Position)
+}
+
+// PositionalCountError is returned when a variadic positional value received
+// fewer values than its minimum or more than its maximum.
+type PositionalCountError struct {
+	Subcommand string // the subcommand owning the positional, or empty for the root parser
+	Name       string // the name of the positional value
+	Min        int    // the fewest values accepted
+	Max        int    // the most values accepted, or 0 for no limit
+	Count      int    // the number of values supplied
+}
+
+func (e *PositionalCountError) Error() string {
+	var expected string
+	switch {
+	case e.Max == 0:
+		expected = "at least " + strconv.Itoa(e.Min)
+	case e.Min == e.Max:
+		expected = strconv.Itoa(e.Min)
+	default:
+		expected = "between " + strconv.Itoa(e.Min) + " and " + strconv.Itoa(e.Max)
+	}
+	msg := "Positional " + e.Name + " expects " + expected + " values, but " + strconv.Itoa(e.Count) + " were supplied"
+	if e.Subcommand != "" {
+		msg = "Positional of subcommand " + e.Subcommand + " named " + e.Name + " expects " + expected + " values, but " + strconv.Itoa(e.Count) + " were supplied"
+	}
+	return msg
+}
+
+// RequiredFlagError is returned when flags marked as Required were not
+// given a value on the command line, by the environment, or by a config
+// file.  Every missing flag is reported together.
+type RequiredFlagError struct {
+	Flags []string // the missing flags, as they would be typed on the command line
+}
+
+func (e *RequiredFlagError) Error() string {
+	if len(e.Flags) == 1 {
+		return "Required flag not supplied: " + e.Flags[0]
+	}
+	return "Required flags not supplied: " + strings.Join(e.Flags, ", ")
+}
+
+// ConversionError is returned when a value could not be converted into the
+// type of the flag it was supplied for.
+type ConversionError struct {
+	Flag       string // the name of the flag, as supplied, or the name of the positional value
+	Value      string // the raw value that failed to convert
+	Position   int    // the index of the flag in the parsed arguments, the relative position of a positional value, or -1 when not from the command line
+	Positional bool   // indicates the value was supplied for a positional value
+	Err        error  // the underlying conversion error
+}
+
+func (e *ConversionError) Error() string {
+	if e.Positional {
+		return "Unable to convert value '" + e.Value + "' for positional value " + e.Flag + " at position " + strconv.Itoa(e.Position) + ": " + e.Err.Error()
+	}
+	return "Unable to convert value '" + e.Value + "' for flag " + e.Flag + ": " + e.Err.Error()
+}
+
+// Unwrap returns the underlying conversion error.
+func (e *ConversionError) Unwrap() error {
+	return e.Err
+}
+
+// BuiltinFlagConflictError is returned when a flag is named like the
+// builtin help or version flags while they are enabled.
+type BuiltinFlagConflictError struct {
+	Flag    string // the conflicting name of the flag
+	Version bool   // indicates the conflict is with --version instead of -h or --help
+}
+
+func (e *BuiltinFlagConflictError) Error() string {
+	if e.Version {
+		return `Flag with name '` + e.Flag + `' conflicts with the internal --version flag in flaggy.
+
+You must either change the flag's name, or disable flaggy's internal version
+flag with 'flaggy.DefaultParser.ShowVersionWithVersionFlag = false'.  If you are using
+a custom parser, you must instead set '.ShowVersionWithVersionFlag = false' on it.`
+	}
+	return `Flag with name '` + e.Flag + `' conflicts with the internal --help or -h flag in flaggy.
+
+You must either change the flag's name, or disable flaggy's internal help
+flag with 'flaggy.DefaultParser.ShowHelpWithHFlag = false'.  If you are using
+a custom parser, you must instead set '.ShowHelpWithHFlag = false' on it.`
+}
+
+// AliasConflictError is returned when an alias of a flag is also the name or
+// alias of another flag on the same subcommand.
+type AliasConflictError struct {
+	Subcommand string // the subcommand owning the flags, or empty for the root parser
+	Alias      string // the alias claimed by more than one flag
+}
+
+func (e *AliasConflictError) Error() string {
+	if e.Subcommand == "" {
+		return "Flag alias " + e.Alias + " is assigned to more than one flag."
+	}
+	return "Flag alias " + e.Alias + " on subcommand " + e.Subcommand + " is assigned to more than one flag."
+}
+
+// isUsageError reports whether the error was caused by invalid arguments,
+// as opposed to a failure converting a value.
+func isUsageError(err error) bool {
+	var unknown *UnknownArgumentError
+	var missing *MissingValueError
+	var required *RequiredPositionalError
+	var requiredFlags *RequiredFlagError
+	var group *FlagGroupError
+	var count *PositionalCountError
+	return errors.As(err, &unknown) || errors.As(err, &missing) || errors.As(err, &required) ||
+		errors.As(err, &requiredFlags) || errors.As(err, &group) || errors.As(err, &count)
+}
+
+// locateFlagArg finds the index of the argument that supplied the value for
+// the named flag, or -1 when it can not be found.
+func locateFlagArg(args []string, key string, value string) int {
+	for i, a := range args {
+		switch determineArgType(a) {
+		case argIsFinal:
+			return -1
+		case argIsFlagWithSpace:
+			if parseFlagToName(a) == key && i+1 < len(args) && args[i+1] == value {
+				return i
+			}
+		case argIsFlagWithValue:
+			k, v := parseArgWithValue(a)
+			if k == key && v == value {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// handleError applies the parser's ErrorHandling to an error produced while
+// parsing.
+func (p *Parser) handleError(err error) error {
+	switch {
+	case errors.Is(err, ErrHelp):
+		p.ShowHelp()
+		return p.stopParsing(0, err)
+	case errors.Is(err, ErrVersion):
+		p.showVersion()
+		return p.stopParsing(0, err)
+	}
+
+	switch p.ErrorHandling {
+	case PanicOnError:
+		panic(err)
+	case ExitOnError:
+		var conflict *BuiltinFlagConflictError
+		var alias *AliasConflictError
+		if errors.As(err, &conflict) || errors.As(err, &alias) {
+			fmt.Println(err)
+			p.exit(1)
+		}
+		if isUsageError(err) {
+			p.ShowHelpWithMessage(err.Error())
+			p.exit(2)
+		}
+	}
+	return err
+}
+
+// stopParsing ends parsing after help, version, or completion output has
+// been shown, exiting with the supplied code when the parser exits on errors.
+func (p *Parser) stopParsing(code int, err error) error {
+	switch p.ErrorHandling {
+	case ExitOnError:
+		p.exit(code)
+	case PanicOnError:
+		panic(err)
+	}
+	return err
+}
@@ -0,0 +1,188 @@
+package flaggy_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestContinueOnErrorUnknownArgument(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var name string
+	p.String(&name, "n", "name", "a name")
+	err := p.ParseArgs([]string{"--bogus"})
+	var unknown *flaggy.UnknownArgumentError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected *UnknownArgumentError, got %T: %v", err, err)
+	}
+	if len(unknown.Arguments) != 1 || unknown.Arguments[0] != "bogus" {
+		t.Fatalf("unexpected arguments in error: %v", unknown.Arguments)
+	}
+}
+
+func TestContinueOnErrorUnexpectedPositional(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	sub := flaggy.NewSubcommand("deploy")
+	p.AttachSubcommand(sub, 1)
+	err := p.ParseArgs([]string{"depoly"})
+	var unknown *flaggy.UnknownArgumentError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected *UnknownArgumentError, got %T: %v", err, err)
+	}
+	if unknown.Position != 1 || len(unknown.Subcommands) != 1 || unknown.Subcommands[0] != "deploy" {
+		t.Fatalf("unexpected error contents: %+v", unknown)
+	}
+}
+
+func TestContinueOnErrorMissingValue(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var name string
+	p.String(&name, "n", "name", "a name")
+	err := p.ParseArgs([]string{"--name"})
+	var missing *flaggy.MissingValueError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected *MissingValueError, got %T: %v", err, err)
+	}
+	if missing.Flag != "name" {
+		t.Fatalf("expected flag name in error, got %q", missing.Flag)
+	}
+}
+
+func TestContinueOnErrorRequiredPositional(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	sub := flaggy.NewSubcommand("get")
+	var target string
+	sub.AddPositionalValue(&target, "target", 1, true, "the target")
+	p.AttachSubcommand(sub, 1)
+	err := p.ParseArgs([]string{"get"})
+	var required *flaggy.RequiredPositionalError
+	if !errors.As(err, &required) {
+		t.Fatalf("expected *RequiredPositionalError, got %T: %v", err, err)
+	}
+	if required.Subcommand != "get" || required.Name != "target" || required.Position != 1 {
+		t.Fatalf("unexpected error contents: %+v", required)
+	}
+}
+
+func TestConversionErrorPosition(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var port int
+	var debug bool
+	p.Bool(&debug, "d", "debug", "debug output")
+	p.Int(&port, "p", "port", "listen port")
+	err := p.ParseArgs([]string{"-d", "--port", "eighty"})
+	var conversion *flaggy.ConversionError
+	if !errors.As(err, &conversion) {
+		t.Fatalf("expected *ConversionError, got %T: %v", err, err)
+	}
+	if conversion.Flag != "port" || conversion.Value != "eighty" || conversion.Position != 1 {
+		t.Fatalf("unexpected error contents: %+v", conversion)
+	}
+	if conversion.Unwrap() == nil {
+		t.Fatal("expected the underlying conversion error to be kept")
+	}
+}
+
+func TestContinueOnErrorHelpAndVersion(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	if err := p.ParseArgs([]string{"--help"}); !errors.Is(err, flaggy.ErrHelp) {
+		t.Fatalf("expected ErrHelp, got %v", err)
+	}
+
+	p = flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.Version = "1.0.0"
+	if err := p.ParseArgs([]string{"--version"}); !errors.Is(err, flaggy.ErrVersion) {
+		t.Fatalf("expected ErrVersion, got %v", err)
+	}
+}
+
+func TestPanicOnError(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.PanicOnError
+	defer func() {
+		r := recover()
+		err, ok := r.(error)
+		if !ok {
+			t.Fatalf("expected panic with an error, got %v", r)
+		}
+		if !strings.Contains(err.Error(), "bogus") {
+			t.Fatalf("expected panic to describe the argument, got %v", err)
+		}
+	}()
+	p.ParseArgs([]string{"--bogus"})
+	t.Fatal("expected parse to panic")
+}
+
+func TestPackageParseArgsContinueOnError(t *testing.T) {
+	flaggy.ResetParser()
+	defer flaggy.ResetParser()
+	flaggy.SetErrorHandling(flaggy.ContinueOnError)
+	if err := flaggy.ParseArgs([]string{"-h"}); !errors.Is(err, flaggy.ErrHelp) {
+		t.Fatalf("expected ErrHelp, got %v", err)
+	}
+
+	flaggy.ResetParser()
+	flaggy.SetErrorHandling(flaggy.ContinueOnError)
+	err := flaggy.ParseArgs([]string{"--bogus"})
+	var unknown *flaggy.UnknownArgumentError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected *UnknownArgumentError, got %T: %v", err, err)
+	}
+}
+
+func TestContinueOnErrorCompletion(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	if err := p.ParseArgs([]string{"completion"}); flaggy.ExitCode(err) != 2 {
+		t.Fatalf("expected an error with exit code 2, got %v", err)
+	}
+}
+
+func TestContinueOnErrorBuiltinFlagConflict(t *testing.T) {
+	tests := []struct {
+		short, long string
+		version     bool
+	}{
+		{long: "help"},
+		{short: "h", long: "host"},
+		{long: "version", version: true},
+	}
+	for _, tt := range tests {
+		p := flaggy.NewParser("app")
+		p.ErrorHandling = flaggy.ContinueOnError
+		sub := flaggy.NewSubcommand("serve")
+		var value string
+		sub.String(&value, tt.short, tt.long, "conflicts with a builtin flag")
+		p.AttachSubcommand(sub, 1)
+		err := p.ParseArgs([]string{"serve"})
+		var conflict *flaggy.BuiltinFlagConflictError
+		if !errors.As(err, &conflict) || conflict.Version != tt.version {
+			t.Fatalf("expected a builtin conflict for %s/%s, got %T: %v", tt.short, tt.long, err, err)
+		}
+		if flaggy.ExitCode(err) != 1 {
+			t.Fatalf("expected exit code 1 for a builtin conflict, got %d", flaggy.ExitCode(err))
+		}
+	}
+}
+
+func TestExitOnErrorBuiltinFlagConflict(t *testing.T) {
+	p := flaggy.NewParser("app")
+	var help bool
+	p.Bool(&help, "", "help", "conflicts with the builtin help flag")
+	defer func() {
+		if r := recover(); r != "Panic instead of exit with code: 1" {
+			t.Fatalf("expected exit with code 1, got %v", r)
+		}
+	}()
+	p.ParseArgs([]string{})
+	t.Fatal("expected parse to exit")
+}
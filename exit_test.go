@@ -0,0 +1,10 @@
+package flaggy
+
+import "strconv"
+
+// init makes every exit panic during tests so that the tests can recover.
+func init() {
+	osExit = func(code int) {
+		panic("Panic instead of exit with code: " + strconv.Itoa(code))
+	}
+}
@@ -1,16 +1,9 @@
 package flaggy
 
 import (
-	"encoding/base64"
 	"errors"
 	"fmt"
-	"math/big"
-	"net"
-	netip "net/netip"
-	"net/url"
-	"os"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -18,14 +11,26 @@ import (
 
 // Flag holds the base methods for all flag types
 type Flag struct {
-	ShortName     string
-	LongName      string
-	Description   string
-	rawValue      string // the value as a string before being parsed
-	Hidden        bool   // indicates this flag should be hidden from help and suggestions
-	AssignmentVar interface{}
-	defaultValue  string // the value (as a string), that was set by default before any parsing and assignment
-	parsed        bool   // indicates that this flag has already been parsed
+	ShortName      string
+	LongName       string
+	Description    string
+	rawValue       string                        // the value as a string before being parsed
+	Hidden         bool                          // indicates this flag should be hidden from help and suggestions
+	EnvVar         string                        // the environment variable that can supply this flag's value
+	Aliases        []string                      // alternate long names that set this flag, in addition to ShortName and LongName
+	Deprecated     string                        // when set, using this flag prints this message as a warning and it is hidden from help and completion
+	Required       bool                          // indicates this flag must be given a value when its subcommand is used
+	Delimiter      string                        // when set on a slice flag, each value is split on this character, with CSV style quoting
+	ReplaceDefault bool                          // when true on a slice flag, the first value supplied replaces the default contents instead of appending to them
+	Validate       func(value interface{}) error // when set, called with the flag's value after each assignment.  Returned errors reject the value and leave the variable unchanged
+	Constraint     string                        // describes the values Validate accepts in help output, like "between 1 and 65535"
+	CompletionFunc CompletionFunc                // when set, returns the candidates offered by shell completion for this flag's value
+	AssignmentVar  interface{}
+	Source         ValueSource // where the flag's current value came from.  Set by the parser
+	defaultValue   string      // the value (as a string), that was set by default before any parsing and assignment
+	parsed         bool        // indicates that this flag has already been parsed
+	delimiter      string      // the SliceDelimiter of the parser this flag belongs to
+	replaceDefault bool        // the ReplaceSliceDefaults setting of the parser this flag belongs to
 }
 
 // HasName indicates that this flag's short or long name matches the
@@ -35,13 +40,44 @@ func (f *Flag) HasName(name string) bool {
 	if f.ShortName == name || f.LongName == name {
 		return true
 	}
+	for _, alias := range f.Aliases {
+		if alias == name {
+			return true
+		}
+	}
 	return false
 }
 
+// hidden reports whether the flag should be left out of help, completion,
+// and suggestions.  Deprecated flags are hidden.
+func (f *Flag) hidden() bool {
+	return f.Hidden || f.Deprecated != ""
+}
+
+// displayName returns the flag as it would be typed on the command line,
+// preferring the long name.
+func (f *Flag) displayName() string {
+	if f.LongName != "" {
+		return "--" + f.LongName
+	}
+	return "-" + f.ShortName
+}
+
+// isBool reports whether this flag is set without a following value, which
+// is the case for bools, bool slices and custom values that report
+// IsBoolFlag.
+func (f *Flag) isBool() bool {
+	switch f.AssignmentVar.(type) {
+	case *bool, *[]bool:
+		return true
+	}
+	return isBoolValue(f.AssignmentVar)
+}
+
 // identifyAndAssignValue identifies the type of the incoming value
 // and assigns it to the AssignmentVar pointer's target value.  If
 // the value is a type that needs parsing, that is performed as well.
-func (f *Flag) identifyAndAssignValue(value string) error {
+func (f *Flag) identifyAndAssignValue(value string, source ValueSource) error {
 
 	var err error
 
@@ -59,442 +95,47 @@ func (f *Flag) identifyAndAssignValue(value string) error {
 	debugPrint("attempting to assign value", value, "to flag", f.LongName)
 	f.rawValue = value // remember the raw value
 
-	// depending on the type of the assignment variable, we convert the
-	// incoming string and assign it.  We only use pointers to variables
-	// in flagy.  No returning vars by value.
-	switch f.AssignmentVar.(type) {
-	case *string:
-		v, _ := (f.AssignmentVar).(*string)
-		*v = value
-	case *[]string:
-		v := f.AssignmentVar.(*[]string)
-		new := append(*v, value)
-		*v = new
-	case *bool:
-		v, err := strconv.ParseBool(value)
-		if err != nil {
-			return err
-		}
-		a, _ := (f.AssignmentVar).(*bool)
-		*a = v
-	case *[]bool:
-		// parse the incoming bool
-		b, err := strconv.ParseBool(value)
-		if err != nil {
-			return err
-		}
-		// cast the assignment var
-		existing := f.AssignmentVar.(*[]bool)
-		// deref the assignment var and append to it
-		v := append(*existing, b)
-		// pointer the new value and assign it
-		a, _ := (f.AssignmentVar).(*[]bool)
-		*a = v
-	case *time.Duration:
-		v, err := time.ParseDuration(value)
-		if err != nil {
-			return err
-		}
-		a, _ := (f.AssignmentVar).(*time.Duration)
-		*a = v
-	case *[]time.Duration:
-		t, err := time.ParseDuration(value)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*[]time.Duration)
-		// deref the assignment var and append to it
-		v := append(*existing, t)
-		// pointer the new value and assign it
-		a, _ := (f.AssignmentVar).(*[]time.Duration)
-		*a = v
-	case *float32:
-		v, err := strconv.ParseFloat(value, 32)
-		if err != nil {
-			return err
-		}
-		float := float32(v)
-		a, _ := (f.AssignmentVar).(*float32)
-		*a = float
-	case *[]float32:
-		v, err := strconv.ParseFloat(value, 32)
-		if err != nil {
-			return err
-		}
-		float := float32(v)
-		existing := f.AssignmentVar.(*[]float32)
-		new := append(*existing, float)
-		*existing = new
-	case *float64:
-		v, err := strconv.ParseFloat(value, 64)
-		if err != nil {
-			return err
-		}
-		a, _ := (f.AssignmentVar).(*float64)
-		*a = v
-	case *[]float64:
-		v, err := strconv.ParseFloat(value, 64)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*[]float64)
-		new := append(*existing, v)
-
-		*existing = new
-	case *int:
-		v, err := strconv.Atoi(value)
-		if err != nil {
-			return err
-		}
-		e := f.AssignmentVar.(*int)
-		*e = v
-	case *[]int:
-		v, err := strconv.Atoi(value)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*[]int)
-		new := append(*existing, v)
-		*existing = new
-	case *uint:
-		v, err := strconv.ParseUint(value, 10, 64)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*uint)
-		*existing = uint(v)
-	case *[]uint:
-		v, err := strconv.ParseUint(value, 10, 64)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*[]uint)
-		new := append(*existing, uint(v))
-		*existing = new
-	case *uint64:
-		v, err := strconv.ParseUint(value, 10, 64)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*uint64)
-		*existing = v
-	case *[]uint64:
-		v, err := strconv.ParseUint(value, 10, 64)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*[]uint64)
-		new := append(*existing, v)
-		*existing = new
-	case *uint32:
-		v, err := strconv.ParseUint(value, 10, 32)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*uint32)
-		*existing = uint32(v)
-	case *[]uint32:
-		v, err := strconv.ParseUint(value, 10, 32)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*[]uint32)
-		new := append(*existing, uint32(v))
-		*existing = new
-	case *uint16:
-		v, err := strconv.ParseUint(value, 10, 16)
-		if err != nil {
-			return err
-		}
-		val := uint16(v)
-		existing := f.AssignmentVar.(*uint16)
-		*existing = val
-	case *[]uint16:
-		v, err := strconv.ParseUint(value, 10, 16)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*[]uint16)
-		new := append(*existing, uint16(v))
-		*existing = new
-	case *uint8:
-		v, err := strconv.ParseUint(value, 10, 8)
-		if err != nil {
-			return err
-		}
-		val := uint8(v)
-		existing := f.AssignmentVar.(*uint8)
-		*existing = val
-	case *[]uint8:
-		var newSlice []uint8
-
-		v, err := strconv.ParseUint(value, 10, 8)
-		if err != nil {
-			return err
-		}
-		newV := uint8(v)
-		existing := f.AssignmentVar.(*[]uint8)
-		newSlice = append(*existing, newV)
-		*existing = newSlice
-	case *int64:
-		v, err := strconv.ParseInt(value, 10, 64)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*int64)
-		*existing = v
-	case *[]int64:
-		v, err := strconv.ParseInt(value, 10, 64)
-		if err != nil {
-			return err
+	values := []string{value}
+	if f.isSlice() {
+		// drop the default contents the first time a value is supplied
+		if (f.ReplaceDefault || f.replaceDefault) && !f.Changed() {
+			resetSlice(f.AssignmentVar)
 		}
-		existingSlice := f.AssignmentVar.(*[]int64)
-		newSlice := append(*existingSlice, v)
-		*existingSlice = newSlice
-	case *int32:
-		v, err := strconv.ParseInt(value, 10, 32)
-		if err != nil {
-			return err
+		delimiter := f.Delimiter
+		if delimiter == "" {
+			delimiter = f.delimiter
 		}
-		converted := int32(v)
-		existing := f.AssignmentVar.(*int32)
-		*existing = converted
-	case *[]int32:
-		v, err := strconv.ParseInt(value, 10, 32)
-		if err != nil {
-			return err
-		}
-		existingSlice := f.AssignmentVar.(*[]int32)
-		newSlice := append(*existingSlice, int32(v))
-		*existingSlice = newSlice
-	case *int16:
-		v, err := strconv.ParseInt(value, 10, 16)
-		if err != nil {
-			return err
-		}
-		converted := int16(v)
-		existing := f.AssignmentVar.(*int16)
-		*existing = converted
-	case *[]int16:
-		v, err := strconv.ParseInt(value, 10, 16)
-		if err != nil {
-			return err
-		}
-		existingSlice := f.AssignmentVar.(*[]int16)
-		newSlice := append(*existingSlice, int16(v))
-		*existingSlice = newSlice
-	case *int8:
-		v, err := strconv.ParseInt(value, 10, 8)
-		if err != nil {
-			return err
-		}
-		converted := int8(v)
-		existing := f.AssignmentVar.(*int8)
-		*existing = converted
-	case *[]int8:
-		v, err := strconv.ParseInt(value, 10, 8)
-		if err != nil {
-			return err
-		}
-		existingSlice := f.AssignmentVar.(*[]int8)
-		newSlice := append(*existingSlice, int8(v))
-		*existingSlice = newSlice
-	case *net.IP:
-		v := net.ParseIP(value)
-		existing := f.AssignmentVar.(*net.IP)
-		*existing = v
-	case *[]net.IP:
-		v := net.ParseIP(value)
-		existing := f.AssignmentVar.(*[]net.IP)
-		new := append(*existing, v)
-		*existing = new
-	case *net.HardwareAddr:
-		v, err := net.ParseMAC(value)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*net.HardwareAddr)
-		*existing = v
-	case *[]net.HardwareAddr:
-		v, err := net.ParseMAC(value)
-		if err != nil {
-			return err
-		}
-		existing := f.AssignmentVar.(*[]net.HardwareAddr)
-		new := append(*existing, v)
-		*existing = new
-	case *net.IPMask:
-		v := net.IPMask(net.ParseIP(value).To4())
-		existing := f.AssignmentVar.(*net.IPMask)
-		*existing = v
-	case *[]net.IPMask:
-		v := net.IPMask(net.ParseIP(value).To4())
-		existing := f.AssignmentVar.(*[]net.IPMask)
-		new := append(*existing, v)
-		*existing = new
-	case *time.Time:
-		// Support unix seconds if numeric, else try common layouts
-		if isAllDigits(value) {
-			sec, err := strconv.ParseInt(value, 10, 64)
+		if delimiter != "" {
+			values, err = splitSliceValue(value, delimiter)
 			if err != nil {
 				return err
 			}
-			t := time.Unix(sec, 0).UTC()
-			a := f.AssignmentVar.(*time.Time)
-			*a = t
-			return nil
-		}
-		var parsed time.Time
-		var err error
-		layouts := []string{time.RFC3339Nano, time.RFC3339, time.RFC1123Z, time.RFC1123}
-		for _, layout := range layouts {
-			parsed, err = time.Parse(layout, value)
-			if err == nil {
-				a := f.AssignmentVar.(*time.Time)
-				*a = parsed
-				return nil
-			}
-		}
-		return err
-	case *url.URL:
-		u, err := url.Parse(value)
-		if err != nil {
-			return err
-		}
-		a := f.AssignmentVar.(*url.URL)
-		*a = *u
-	case *net.IPNet:
-		_, ipnet, err := net.ParseCIDR(value)
-		if err != nil {
-			return err
-		}
-		a := f.AssignmentVar.(*net.IPNet)
-		*a = *ipnet
-	case *net.TCPAddr:
-		host, portStr, err := net.SplitHostPort(value)
-		if err != nil {
-			return err
-		}
-		port, err := strconv.Atoi(portStr)
-		if err != nil {
-			return err
-		}
-		var ip net.IP
-		if len(host) > 0 {
-			ip = net.ParseIP(host)
-		}
-		addr := net.TCPAddr{IP: ip, Port: port}
-		a := f.AssignmentVar.(*net.TCPAddr)
-		*a = addr
-	case *net.UDPAddr:
-		host, portStr, err := net.SplitHostPort(value)
-		if err != nil {
-			return err
-		}
-		port, err := strconv.Atoi(portStr)
-		if err != nil {
-			return err
-		}
-		var ip net.IP
-		if len(host) > 0 {
-			ip = net.ParseIP(host)
-		}
-		addr := net.UDPAddr{IP: ip, Port: port}
-		a := f.AssignmentVar.(*net.UDPAddr)
-		*a = addr
-	case *os.FileMode:
-		v, err := strconv.ParseUint(value, 0, 32)
-		if err != nil {
-			return err
-		}
-		a := f.AssignmentVar.(*os.FileMode)
-		*a = os.FileMode(v)
-	case *regexp.Regexp:
-		r, err := regexp.Compile(value)
-		if err != nil {
-			return err
-		}
-		a := f.AssignmentVar.(*regexp.Regexp)
-		*a = *r
-	case *time.Location:
-		// Try IANA name, with fallback to UTC offset like +02:00 or -0700
-		if loc, err := time.LoadLocation(value); err == nil {
-			a := f.AssignmentVar.(*time.Location)
-			*a = *loc
-			return nil
-		}
-		if off, ok := parseUTCOffset(value); ok {
-			name := offsetName(off)
-			loc := time.FixedZone(name, off)
-			a := f.AssignmentVar.(*time.Location)
-			*a = *loc
-			return nil
-		}
-		return fmt.Errorf("invalid time.Location: %s", value)
-	case *time.Month:
-		if m, ok := parseMonth(value); ok {
-			a := f.AssignmentVar.(*time.Month)
-			*a = m
-			return nil
 		}
-		return fmt.Errorf("invalid time.Month: %s", value)
-	case *time.Weekday:
-		if d, ok := parseWeekday(value); ok {
-			a := f.AssignmentVar.(*time.Weekday)
-			*a = d
-			return nil
-		}
-		return fmt.Errorf("invalid time.Weekday: %s", value)
-	case *big.Int:
-		bi := f.AssignmentVar.(*big.Int)
-		if _, ok := bi.SetString(value, 0); !ok {
-			return fmt.Errorf("invalid big.Int: %s", value)
-		}
-	case *big.Rat:
-		br := f.AssignmentVar.(*big.Rat)
-		if _, ok := br.SetString(value); !ok {
-			return fmt.Errorf("invalid big.Rat: %s", value)
-		}
-	case *Base64Bytes:
-		// Try standard then URL encoding
-		decoded, err := base64.StdEncoding.DecodeString(value)
-		if err == nil {
-			a := f.AssignmentVar.(*Base64Bytes)
-			*a = Base64Bytes(decoded)
-			return nil
-		}
-		if decodedURL, errURL := base64.URLEncoding.DecodeString(value); errURL == nil {
-			a := f.AssignmentVar.(*Base64Bytes)
-			*a = Base64Bytes(decodedURL)
-			return nil
-		}
-		return err
-	case *netip.Addr:
-		addr, err := netip.ParseAddr(value)
-		if err != nil {
-			return err
-		}
-		a := f.AssignmentVar.(*netip.Addr)
-		*a = addr
-	case *netip.Prefix:
-		pfx, err := netip.ParsePrefix(value)
-		if err != nil {
-			return err
+	}
+	f.Source = source
+
+	for _, v := range values {
+		handled, err := assignValue(f.AssignmentVar, v)
+		if !handled {
+			return errors.New("Unknown flag assignmentVar supplied in flag " + f.LongName + " " + f.ShortName)
 		}
-		a := f.AssignmentVar.(*netip.Prefix)
-		*a = pfx
-	case *netip.AddrPort:
-		ap, err := netip.ParseAddrPort(value)
 		if err != nil {
 			return err
 		}
-		a := f.AssignmentVar.(*netip.AddrPort)
-		*a = ap
-	default:
-		return errors.New("Unknown flag assignmentVar supplied in flag " + f.LongName + " " + f.ShortName)
 	}
+	return nil
+}
 
-	return err
+// assignValue converts the incoming string to the type of the assignment
+// variable and assigns it.  The returned bool is false when the type of the
+// assignment variable is not supported.
+func assignValue(assignmentVar interface{}, value string) (bool, error) {
+	if handler, ok := lookupType(assignmentVar); ok {
+		return true, handler.assign(assignmentVar, value)
+	}
+	// fall back to the Value, flag.Value and TextUnmarshaler interfaces
+	// so custom types can be used without being registered
+	return assignCustomValue(assignmentVar, value)
 }
 
 const argIsPositional = "positional"       // subcommand or positional value
@@ -588,21 +229,13 @@ func collectAllNestedFlags(sc *Subcommand) []*Flag {
 func flagIsBool(sc *Subcommand, p *Parser, key string) bool {
 	for _, f := range sc.Flags {
 		if f.HasName(key) {
-			_, isBool := f.AssignmentVar.(*bool)
-			_, isBoolSlice := f.AssignmentVar.(*[]bool)
-			if isBool || isBoolSlice {
-				return true
-			}
+			return f.isBool()
 		}
 	}
 
 	for _, f := range p.Flags {
 		if f.HasName(key) {
-			_, isBool := f.AssignmentVar.(*bool)
-			_, isBoolSlice := f.AssignmentVar.(*[]bool)
-			if isBool || isBoolSlice {
-				return true
-			}
+			return f.isBool()
 		}
 	}
 
@@ -635,251 +268,22 @@ func (f *Flag) returnAssignmentVarValueAsString() (string, error) {
 
 	debugPrint("returning current value of assignment var of flag", f.LongName)
 
-	var err error
-
-	// depending on the type of the assignment variable, we convert the
-	// incoming string and assign it.  We only use pointers to variables
-	// in flagy.  No returning vars by value.
-	switch f.AssignmentVar.(type) {
-	case *string:
-		v, _ := (f.AssignmentVar).(*string)
-		return *v, err
-	case *[]string:
-		v := f.AssignmentVar.(*[]string)
-		return strings.Join(*v, ","), err
-	case *bool:
-		a, _ := (f.AssignmentVar).(*bool)
-		return strconv.FormatBool(*a), err
-	case *[]bool:
-		value := f.AssignmentVar.(*[]bool)
-		var ss []string
-		for _, b := range *value {
-			ss = append(ss, strconv.FormatBool(b))
-		}
-		return strings.Join(ss, ","), err
-	case *time.Duration:
-		a := f.AssignmentVar.(*time.Duration)
-		return (*a).String(), err
-	case *[]time.Duration:
-		tds := f.AssignmentVar.(*[]time.Duration)
-		var asSlice []string
-		for _, td := range *tds {
-			asSlice = append(asSlice, td.String())
-		}
-		return strings.Join(asSlice, ","), err
-	case *float32:
-		a := f.AssignmentVar.(*float32)
-		return strconv.FormatFloat(float64(*a), 'f', 2, 32), err
-	case *[]float32:
-		v := f.AssignmentVar.(*[]float32)
-		var strSlice []string
-		for _, f := range *v {
-			formatted := strconv.FormatFloat(float64(f), 'f', 2, 32)
-			strSlice = append(strSlice, formatted)
-		}
-		return strings.Join(strSlice, ","), err
-	case *float64:
-		a := f.AssignmentVar.(*float64)
-		return strconv.FormatFloat(float64(*a), 'f', 2, 64), err
-	case *[]float64:
-		v := f.AssignmentVar.(*[]float64)
-		var strSlice []string
-		for _, f := range *v {
-			formatted := strconv.FormatFloat(float64(f), 'f', 2, 64)
-			strSlice = append(strSlice, formatted)
-		}
-		return strings.Join(strSlice, ","), err
-	case *int:
-		a := f.AssignmentVar.(*int)
-		return strconv.Itoa(*a), err
-	case *[]int:
-		val := f.AssignmentVar.(*[]int)
-		var strSlice []string
-		for _, i := range *val {
-			str := strconv.Itoa(i)
-			strSlice = append(strSlice, str)
-		}
-		return strings.Join(strSlice, ","), err
-	case *uint:
-		v := f.AssignmentVar.(*uint)
-		return strconv.FormatUint(uint64(*v), 10), err
-	case *[]uint:
-		values := f.AssignmentVar.(*[]uint)
-		var strVars []string
-		for _, i := range *values {
-			strVars = append(strVars, strconv.FormatUint(uint64(i), 10))
-		}
-		return strings.Join(strVars, ","), err
-	case *uint64:
-		v := f.AssignmentVar.(*uint64)
-		return strconv.FormatUint(*v, 10), err
-	case *[]uint64:
-		values := f.AssignmentVar.(*[]uint64)
-		var strVars []string
-		for _, i := range *values {
-			strVars = append(strVars, strconv.FormatUint(i, 10))
-		}
-		return strings.Join(strVars, ","), err
-	case *uint32:
-		v := f.AssignmentVar.(*uint32)
-		return strconv.FormatUint(uint64(*v), 10), err
-	case *[]uint32:
-		values := f.AssignmentVar.(*[]uint32)
-		var strVars []string
-		for _, i := range *values {
-			strVars = append(strVars, strconv.FormatUint(uint64(i), 10))
-		}
-		return strings.Join(strVars, ","), err
-	case *uint16:
-		v := f.AssignmentVar.(*uint16)
-		return strconv.FormatUint(uint64(*v), 10), err
-	case *[]uint16:
-		values := f.AssignmentVar.(*[]uint16)
-		var strVars []string
-		for _, i := range *values {
-			strVars = append(strVars, strconv.FormatUint(uint64(i), 10))
-		}
-		return strings.Join(strVars, ","), err
-	case *uint8:
-		v := f.AssignmentVar.(*uint8)
-		return strconv.FormatUint(uint64(*v), 10), err
-	case *[]uint8:
-		values := f.AssignmentVar.(*[]uint8)
-		var strVars []string
-		for _, i := range *values {
-			strVars = append(strVars, strconv.FormatUint(uint64(i), 10))
-		}
-		return strings.Join(strVars, ","), err
-	case *int64:
-		v := f.AssignmentVar.(*int64)
-		return strconv.FormatInt(int64(*v), 10), err
-	case *[]int64:
-		values := f.AssignmentVar.(*[]int64)
-		var strVars []string
-		for _, i := range *values {
-			strVars = append(strVars, strconv.FormatInt(i, 10))
-		}
-		return strings.Join(strVars, ","), err
-	case *int32:
-		v := f.AssignmentVar.(*int32)
-		return strconv.FormatInt(int64(*v), 10), err
-	case *[]int32:
-		values := f.AssignmentVar.(*[]int32)
-		var strVars []string
-		for _, i := range *values {
-			strVars = append(strVars, strconv.FormatInt(int64(i), 10))
-		}
-		return strings.Join(strVars, ","), err
-	case *int16:
-		v := f.AssignmentVar.(*int16)
-		return strconv.FormatInt(int64(*v), 10), err
-	case *[]int16:
-		values := f.AssignmentVar.(*[]int16)
-		var strVars []string
-		for _, i := range *values {
-			strVars = append(strVars, strconv.FormatInt(int64(i), 10))
-		}
-		return strings.Join(strVars, ","), err
-	case *int8:
-		v := f.AssignmentVar.(*int8)
-		return strconv.FormatInt(int64(*v), 10), err
-	case *[]int8:
-		values := f.AssignmentVar.(*[]int8)
-		var strVars []string
-		for _, i := range *values {
-			strVars = append(strVars, strconv.FormatInt(int64(i), 10))
-		}
-		return strings.Join(strVars, ","), err
-	case *net.IP:
-		val := f.AssignmentVar.(*net.IP)
-		return val.String(), err
-	case *[]net.IP:
-		val := f.AssignmentVar.(*[]net.IP)
-		var strSlice []string
-		for _, ip := range *val {
-			strSlice = append(strSlice, ip.String())
-		}
-		return strings.Join(strSlice, ","), err
-	case *net.HardwareAddr:
-		val := f.AssignmentVar.(*net.HardwareAddr)
-		return val.String(), err
-	case *[]net.HardwareAddr:
-		val := f.AssignmentVar.(*[]net.HardwareAddr)
-		var strSlice []string
-		for _, mac := range *val {
-			strSlice = append(strSlice, mac.String())
-		}
-		return strings.Join(strSlice, ","), err
-	case *net.IPMask:
-		val := f.AssignmentVar.(*net.IPMask)
-		return val.String(), err
-	case *[]net.IPMask:
-		val := f.AssignmentVar.(*[]net.IPMask)
-		var strSlice []string
-		for _, m := range *val {
-			strSlice = append(strSlice, m.String())
-		}
-		return strings.Join(strSlice, ","), err
-	case *time.Time:
-		v := f.AssignmentVar.(*time.Time)
-		if v.IsZero() {
-			return "", err
-		}
-		return v.UTC().Format(time.RFC3339Nano), err
-	case *url.URL:
-		v := f.AssignmentVar.(*url.URL)
-		return v.String(), err
-	case *net.IPNet:
-		v := f.AssignmentVar.(*net.IPNet)
-		return v.String(), err
-	case *net.TCPAddr:
-		v := f.AssignmentVar.(*net.TCPAddr)
-		return v.String(), err
-	case *net.UDPAddr:
-		v := f.AssignmentVar.(*net.UDPAddr)
-		return v.String(), err
-	case *os.FileMode:
-		v := f.AssignmentVar.(*os.FileMode)
-		return fmt.Sprintf("%#o", *v), err
-	case *regexp.Regexp:
-		v := f.AssignmentVar.(*regexp.Regexp)
-		return v.String(), err
-	case *time.Location:
-		v := f.AssignmentVar.(*time.Location)
-		return v.String(), err
-	case *time.Month:
-		v := f.AssignmentVar.(*time.Month)
-		if *v == 0 {
-			return "", err
-		}
-		return v.String(), err
-	case *time.Weekday:
-		v := f.AssignmentVar.(*time.Weekday)
-		return v.String(), err
-	case *big.Int:
-		v := f.AssignmentVar.(*big.Int)
-		return v.String(), err
-	case *big.Rat:
-		v := f.AssignmentVar.(*big.Rat)
-		return v.RatString(), err
-	case *Base64Bytes:
-		v := f.AssignmentVar.(*Base64Bytes)
-		if v == nil || len(*v) == 0 {
-			return "", err
-		}
-		return base64.StdEncoding.EncodeToString([]byte(*v)), err
-	case *netip.Addr:
-		v := f.AssignmentVar.(*netip.Addr)
-		return v.String(), err
-	case *netip.Prefix:
-		v := f.AssignmentVar.(*netip.Prefix)
-		return v.String(), err
-	case *netip.AddrPort:
-		v := f.AssignmentVar.(*netip.AddrPort)
-		return v.String(), err
-	default:
+	str, handled, err := valueAsString(f.AssignmentVar)
+	if !handled {
 		return "", errors.New("Unknown flag assignmentVar found in flag " + f.LongName + " " + f.ShortName + ". Type not supported: " + reflect.TypeOf(f.AssignmentVar).String())
 	}
+	return str, err
+}
+
+// valueAsString returns the value of the assignment variable as a string.
+// The returned bool is false when the type of the assignment variable is not
+// supported.
+func valueAsString(assignmentVar interface{}) (string, bool, error) {
+	if handler, ok := lookupType(assignmentVar); ok {
+		str, err := handler.format(assignmentVar)
+		return str, true, err
+	}
+	return customValueAsString(assignmentVar)
 }
 
 // helpers
@@ -0,0 +1,222 @@
+package flaggy
+
+import (
+	"errors"
+	"log"
+	"strings"
+)
+
+// FlagGroupKind identifies the constraint a flag group places on its flags.
+type FlagGroupKind int
+
+const (
+	// FlagGroupMutuallyExclusive allows at most one flag in the group to be
+	// supplied.
+	FlagGroupMutuallyExclusive FlagGroupKind = iota
+	// FlagGroupRequiredTogether requires every flag in the group once any of
+	// them is supplied.
+	FlagGroupRequiredTogether
+	// FlagGroupOneRequired requires at least one flag in the group.
+	FlagGroupOneRequired
+)
+
+// String returns the label used for the group kind in help output.
+func (k FlagGroupKind) String() string {
+	switch k {
+	case FlagGroupMutuallyExclusive:
+		return "Mutually exclusive"
+	case FlagGroupRequiredTogether:
+		return "Required together"
+	case FlagGroupOneRequired:
+		return "At least one of"
+	}
+	return "Unknown"
+}
+
+// flagGroup is a set of flags on a subcommand that are constrained together.
+type flagGroup struct {
+	Kind  FlagGroupKind
+	Names []string // the short or long names of the flags in the group
+}
+
+// FlagGroupError is returned when the flags supplied on the command line
+// violate a flag group.
+type FlagGroupError struct {
+	Kind     FlagGroupKind
+	Flags    []string // every flag in the group
+	Supplied []string // the flags in the group that were supplied
+}
+
+func (e *FlagGroupError) Error() string {
+	switch e.Kind {
+	case FlagGroupMutuallyExclusive:
+		return "Flags " + strings.Join(e.Supplied, ", ") + " can not be used together"
+	case FlagGroupRequiredTogether:
+		return "Flags " + strings.Join(e.Flags, ", ") + " must be used together, but only " + strings.Join(e.Supplied, ", ") + " was supplied"
+	}
+	return "One of the flags " + strings.Join(e.Flags, ", ") + " is required"
+}
+
+// MutuallyExclusive declares that at most one of the named flags may be
+// supplied when this subcommand is used.  Flags are named by their short or
+// long name.
+func (sc *Subcommand) MutuallyExclusive(names ...string) {
+	sc.addFlagGroup(FlagGroupMutuallyExclusive, names)
+}
+
+// RequiredTogether declares that once any of the named flags is supplied,
+// all of them must be.
+func (sc *Subcommand) RequiredTogether(names ...string) {
+	sc.addFlagGroup(FlagGroupRequiredTogether, names)
+}
+
+// OneRequired declares that at least one of the named flags must be supplied
+// when this subcommand is used.  Combine it with MutuallyExclusive to
+// require exactly one.
+func (sc *Subcommand) OneRequired(names ...string) {
+	sc.addFlagGroup(FlagGroupOneRequired, names)
+}
+
+// MutuallyExclusive declares that at most one of the named flags on the
+// default parser may be supplied.
+func MutuallyExclusive(names ...string) {
+	DefaultParser.MutuallyExclusive(names...)
+}
+
+// RequiredTogether declares that the named flags on the default parser must
+// be supplied together.
+func RequiredTogether(names ...string) {
+	DefaultParser.RequiredTogether(names...)
+}
+
+// OneRequired declares that at least one of the named flags on the default
+// parser must be supplied.
+func OneRequired(names ...string) {
+	DefaultParser.OneRequired(names...)
+}
+
+// addFlagGroup records a new flag group on the subcommand
+func (sc *Subcommand) addFlagGroup(kind FlagGroupKind, names []string) {
+	if len(names) < 2 {
+		log.Panicln("Unable to add flag group to subcommand " + sc.Name + " because it needs at least two flags")
+	}
+	sc.flagGroups = append(sc.flagGroups, flagGroup{Kind: kind, Names: names})
+}
+
+// groupFlags looks up the flags named in a group.  Names that do not match a
+// flag on the subcommand cause a panic, as the group can never be satisfied.
+func (sc *Subcommand) groupFlags(g flagGroup) []*Flag {
+	flags := make([]*Flag, 0, len(g.Names))
+	for _, name := range g.Names {
+		var found *Flag
+		for _, f := range sc.Flags {
+			if f.HasName(name) {
+				found = f
+				break
+			}
+		}
+		if found == nil {
+			log.Panicln("Flag group on subcommand " + sc.Name + " names flag " + name + " but no such flag exists")
+		}
+		flags = append(flags, found)
+	}
+	return flags
+}
+
+// flagDisplayNames returns the flags as they would be typed on the command
+// line, preferring long names.
+func flagDisplayNames(flags []*Flag) []string {
+	names := make([]string, 0, len(flags))
+	for _, f := range flags {
+		names = append(names, f.displayName())
+	}
+	return names
+}
+
+// checkFlagGroups evaluates the flag groups of the root parser and every
+// used subcommand against the flags recorded in ParsedValues, under any of
+// their names or aliases.  Every violated group is reported.
+func (p *Parser) checkFlagGroups() error {
+	supplied := make(map[string]bool)
+	for _, pv := range p.findAllParsedValues() {
+		if pv.IsPositional {
+			continue
+		}
+		key, _ := parseArgWithValue(pv.Key)
+		supplied[key] = true
+	}
+
+	var errs []error
+	subcommands := append([]*Subcommand{&p.Subcommand}, p.usedSubcommandPath()...)
+	for _, sc := range subcommands {
+		for _, g := range sc.flagGroups {
+			flags := sc.groupFlags(g)
+			var found []*Flag
+			for _, f := range flags {
+				for key := range supplied {
+					if f.HasName(key) {
+						found = append(found, f)
+						break
+					}
+				}
+			}
+
+			var violated bool
+			switch g.Kind {
+			case FlagGroupMutuallyExclusive:
+				violated = len(found) > 1
+			case FlagGroupRequiredTogether:
+				violated = len(found) > 0 && len(found) < len(flags)
+			case FlagGroupOneRequired:
+				violated = len(found) == 0
+			}
+			if violated {
+				errs = append(errs, &FlagGroupError{
+					Kind:     g.Kind,
+					Flags:    flagDisplayNames(flags),
+					Supplied: flagDisplayNames(found),
+				})
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// exclusiveWith returns the flags on the subcommand that share a mutually
+// exclusive group with the supplied flag.
+func (sc *Subcommand) exclusiveWith(f *Flag) []*Flag {
+	var conflicts []*Flag
+	for _, g := range sc.flagGroups {
+		if g.Kind != FlagGroupMutuallyExclusive {
+			continue
+		}
+		flags := sc.groupFlags(g)
+		var inGroup bool
+		for _, other := range flags {
+			if other == f {
+				inGroup = true
+			}
+		}
+		if !inGroup {
+			continue
+		}
+		for _, other := range flags {
+			if other != f {
+				conflicts = append(conflicts, other)
+			}
+		}
+	}
+	return conflicts
+}
+
+// flagCompletionNames returns every form of the flag offered for completion.
+func flagCompletionNames(f *Flag) []string {
+	var names []string
+	if f.LongName != "" {
+		names = append(names, "--"+f.LongName)
+	}
+	if f.ShortName != "" {
+		names = append(names, "-"+f.ShortName)
+	}
+	return names
+}
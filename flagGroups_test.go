@@ -0,0 +1,152 @@
+package flaggy_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestFlagGroups(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		kind flaggy.FlagGroupKind
+		ok   bool
+	}{
+		{name: "one source", args: []string{"--file", "in.txt"}, ok: true},
+		{name: "bool source", args: []string{"-s"}, ok: true},
+		{name: "tls pair", args: []string{"-u=http://x", "--tls-cert", "c", "--tls-key=k"}, ok: true},
+		{name: "two sources", args: []string{"-f", "in.txt", "--url", "http://x"}, kind: flaggy.FlagGroupMutuallyExclusive},
+		{name: "no source", args: []string{}, kind: flaggy.FlagGroupOneRequired},
+		{name: "half tls pair", args: []string{"-s", "--tls-cert", "c"}, kind: flaggy.FlagGroupRequiredTogether},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := flaggy.NewParser("app")
+			p.ErrorHandling = flaggy.ContinueOnError
+			var file, url, cert, key string
+			var stdin bool
+			p.String(&file, "f", "file", "read from a file")
+			p.String(&url, "u", "url", "read from a url")
+			p.Bool(&stdin, "s", "stdin", "read from stdin")
+			p.String(&cert, "", "tls-cert", "certificate file")
+			p.String(&key, "", "tls-key", "key file")
+			p.MutuallyExclusive("file", "url", "stdin")
+			p.OneRequired("file", "url", "stdin")
+			p.RequiredTogether("tls-cert", "tls-key")
+			err := p.ParseArgs(tt.args)
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var group *flaggy.FlagGroupError
+			if !errors.As(err, &group) {
+				t.Fatalf("expected *FlagGroupError, got %T: %v", err, err)
+			}
+			if group.Kind != tt.kind {
+				t.Fatalf("expected %v violation, got %v: %v", tt.kind, group.Kind, err)
+			}
+		})
+	}
+}
+
+func TestFlagGroupErrorNamesFlags(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var file, url string
+	p.String(&file, "f", "file", "read from a file")
+	p.String(&url, "u", "url", "read from a url")
+	p.MutuallyExclusive("file", "url")
+	err := p.ParseArgs([]string{"-f", "in.txt", "-u", "http://x"})
+	if err == nil || !strings.Contains(err.Error(), "--file, --url can not be used together") {
+		t.Fatalf("expected error naming the conflicting flags, got %v", err)
+	}
+}
+
+func TestFlagGroupsMatchAliases(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var listen, socket string
+	p.String(&listen, "", "listen", "address to listen on").Aliases = []string{"addr"}
+	p.String(&socket, "", "socket", "socket to listen on")
+	p.MutuallyExclusive("listen", "socket")
+	err := p.ParseArgs([]string{"--addr", "x", "--socket", "y"})
+	var group *flaggy.FlagGroupError
+	if !errors.As(err, &group) || group.Kind != flaggy.FlagGroupMutuallyExclusive {
+		t.Fatalf("expected mutually exclusive violation through an alias, got %v", err)
+	}
+}
+
+func TestFlagGroupsOnUnusedSubcommand(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	sub := flaggy.NewSubcommand("fetch")
+	var file, url string
+	sub.String(&file, "f", "file", "read from a file")
+	sub.String(&url, "u", "url", "read from a url")
+	sub.OneRequired("file", "url")
+	p.AttachSubcommand(sub, 1)
+	if err := p.ParseArgs([]string{}); err != nil {
+		t.Fatalf("expected groups of unused subcommands to be ignored, got %v", err)
+	}
+}
+
+func TestFlagGroupsShownInHelp(t *testing.T) {
+	p := flaggy.NewParser("app")
+	var file, url, cert, key string
+	var stdin bool
+	p.String(&file, "f", "file", "read from a file")
+	p.String(&url, "u", "url", "read from a url")
+	p.Bool(&stdin, "s", "stdin", "read from stdin")
+	p.String(&cert, "", "tls-cert", "certificate file")
+	p.String(&key, "", "tls-key", "key file")
+	p.MutuallyExclusive("file", "url", "stdin")
+	p.OneRequired("file", "url", "stdin")
+	p.RequiredTogether("tls-cert", "tls-key")
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	out := strings.Join(h.Lines, "\n")
+	for _, want := range []string{
+		"  Flag Groups:",
+		"    Mutually exclusive: --file, --url, --stdin",
+		"    At least one of: --file, --url, --stdin",
+		"    Required together: --tls-cert, --tls-key",
+	} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected help to contain %q:\n%s", want, out)
+		}
+	}
+}
+
+func TestFlagGroupsInCompletion(t *testing.T) {
+	var file, url string
+	var stdin bool
+	p := flaggy.NewParser("app")
+	p.String(&file, "f", "file", "read from a file")
+	p.String(&url, "u", "url", "read from a url")
+	p.Bool(&stdin, "s", "stdin", "read from stdin")
+	p.MutuallyExclusive("file", "url", "stdin")
+	for _, line := range completeWords(t, p, "--file", "in.txt", "-") {
+		for _, excluded := range []string{"--url", "-u", "--stdin", "-s"} {
+			if strings.HasPrefix(line, excluded+"\t") {
+				t.Fatalf("expected %s to be excluded once --file is used: %q", excluded, line)
+			}
+		}
+	}
+
+	p = flaggy.NewParser("app")
+	p.String(&file, "f", "file", "read from a file")
+	p.String(&url, "u", "url", "read from a url")
+	p.MutuallyExclusive("file", "url")
+	var offered bool
+	for _, line := range completeWords(t, p, "-") {
+		offered = offered || strings.HasPrefix(line, "--url\t")
+	}
+	if !offered {
+		t.Fatal("expected --url to be offered before a conflicting flag is used")
+	}
+}
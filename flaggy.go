@@ -7,6 +7,8 @@
 package flaggy // import "github.com/integrii/flaggy"
 
 import (
+	"encoding"
+	"flag"
 	"fmt"
 	"log"
 	"math/big"
@@ -15,7 +17,6 @@ import (
 	"net/url"
 	"os"
 	"regexp"
-	"strconv"
 	"strings"
 	"time"
 )
@@ -75,311 +76,360 @@ func SortFlagsByLongNameReversed() {
 
 // Parse parses flags as requested in the default package parser.  All trailing arguments
 // that result from parsing are placed in the global TrailingArguments variable.
-func Parse() {
-	err := DefaultParser.Parse()
-	TrailingArguments = DefaultParser.TrailingArguments
-	if err != nil {
-		log.Panicln("Error from argument parser:", err)
-	}
+// Errors are returned when the default parser continues on errors, and
+// panic otherwise.
+func Parse() error {
+	return ParseArgs(os.Args[1:])
 }
 
 // ParseArgs parses the passed args as if they were the arguments to the
 // running binary.  Targets the default main parser for the package.  All trailing
-// arguments are set in the global TrailingArguments variable.
-func ParseArgs(args []string) {
+// arguments are set in the global TrailingArguments variable.  Errors are
+// returned when the default parser continues on errors, and panic otherwise.
+func ParseArgs(args []string) error {
 	err := DefaultParser.ParseArgs(args)
 	TrailingArguments = DefaultParser.TrailingArguments
-	if err != nil {
+	if err != nil && DefaultParser.ErrorHandling != ContinueOnError {
 		log.Panicln("Error from argument parser:", err)
 	}
+	return err
 }
 
 // String adds a new string flag
-func String(assignmentVar *string, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func String(assignmentVar *string, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // StringSlice adds a new slice of strings flag
 // Specify the flag multiple times to fill the slice
-func StringSlice(assignmentVar *[]string, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func StringSlice(assignmentVar *[]string, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Bool adds a new bool flag
-func Bool(assignmentVar *bool, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Bool(assignmentVar *bool, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // BoolSlice adds a new slice of bools flag
 // Specify the flag multiple times to fill the slice
-func BoolSlice(assignmentVar *[]bool, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func BoolSlice(assignmentVar *[]bool, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // ByteSlice adds a new slice of bytes flag
 // Specify the flag multiple times to fill the slice.  Takes hex as input.
-func ByteSlice(assignmentVar *[]byte, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func ByteSlice(assignmentVar *[]byte, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // BytesBase64 adds a new []byte flag parsed from base64 input.
-func BytesBase64(assignmentVar *Base64Bytes, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func BytesBase64(assignmentVar *Base64Bytes, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Duration adds a new time.Duration flag.
 // Input format is described in time.ParseDuration().
 // Example values: 1h, 1h50m, 32s
-func Duration(assignmentVar *time.Duration, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Duration(assignmentVar *time.Duration, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // DurationSlice adds a new time.Duration flag.
 // Input format is described in time.ParseDuration().
 // Example values: 1h, 1h50m, 32s
 // Specify the flag multiple times to fill the slice.
-func DurationSlice(assignmentVar *[]time.Duration, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func DurationSlice(assignmentVar *[]time.Duration, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Float32 adds a new float32 flag.
-func Float32(assignmentVar *float32, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Float32(assignmentVar *float32, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Float32Slice adds a new float32 flag.
 // Specify the flag multiple times to fill the slice.
-func Float32Slice(assignmentVar *[]float32, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Float32Slice(assignmentVar *[]float32, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Float64 adds a new float64 flag.
-func Float64(assignmentVar *float64, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Float64(assignmentVar *float64, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Float64Slice adds a new float64 flag.
 // Specify the flag multiple times to fill the slice.
-func Float64Slice(assignmentVar *[]float64, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Float64Slice(assignmentVar *[]float64, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Int adds a new int flag
-func Int(assignmentVar *int, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Int(assignmentVar *int, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // IntSlice adds a new int slice flag.
 // Specify the flag multiple times to fill the slice.
-func IntSlice(assignmentVar *[]int, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func IntSlice(assignmentVar *[]int, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt adds a new uint flag
-func UInt(assignmentVar *uint, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UInt(assignmentVar *uint, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UIntSlice adds a new uint slice flag.
 // Specify the flag multiple times to fill the slice.
-func UIntSlice(assignmentVar *[]uint, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UIntSlice(assignmentVar *[]uint, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt64 adds a new uint64 flag
-func UInt64(assignmentVar *uint64, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UInt64(assignmentVar *uint64, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt64Slice adds a new uint64 slice flag.
 // Specify the flag multiple times to fill the slice.
-func UInt64Slice(assignmentVar *[]uint64, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UInt64Slice(assignmentVar *[]uint64, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt32 adds a new uint32 flag
-func UInt32(assignmentVar *uint32, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UInt32(assignmentVar *uint32, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt32Slice adds a new uint32 slice flag.
 // Specify the flag multiple times to fill the slice.
-func UInt32Slice(assignmentVar *[]uint32, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UInt32Slice(assignmentVar *[]uint32, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt16 adds a new uint16 flag
-func UInt16(assignmentVar *uint16, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UInt16(assignmentVar *uint16, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt16Slice adds a new uint16 slice flag.
 // Specify the flag multiple times to fill the slice.
-func UInt16Slice(assignmentVar *[]uint16, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UInt16Slice(assignmentVar *[]uint16, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt8 adds a new uint8 flag
-func UInt8(assignmentVar *uint8, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UInt8(assignmentVar *uint8, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt8Slice adds a new uint8 slice flag.
 // Specify the flag multiple times to fill the slice.
-func UInt8Slice(assignmentVar *[]uint8, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UInt8Slice(assignmentVar *[]uint8, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Int64 adds a new int64 flag
-func Int64(assignmentVar *int64, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Int64(assignmentVar *int64, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Int64Slice adds a new int64 slice flag.
 // Specify the flag multiple times to fill the slice.
-func Int64Slice(assignmentVar *[]int64, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Int64Slice(assignmentVar *[]int64, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Int32 adds a new int32 flag
-func Int32(assignmentVar *int32, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Int32(assignmentVar *int32, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Int32Slice adds a new int32 slice flag.
 // Specify the flag multiple times to fill the slice.
-func Int32Slice(assignmentVar *[]int32, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Int32Slice(assignmentVar *[]int32, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Int16 adds a new int16 flag
-func Int16(assignmentVar *int16, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Int16(assignmentVar *int16, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Int16Slice adds a new int16 slice flag.
 // Specify the flag multiple times to fill the slice.
-func Int16Slice(assignmentVar *[]int16, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Int16Slice(assignmentVar *[]int16, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Int8 adds a new int8 flag
-func Int8(assignmentVar *int8, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Int8(assignmentVar *int8, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Int8Slice adds a new int8 slice flag.
 // Specify the flag multiple times to fill the slice.
-func Int8Slice(assignmentVar *[]int8, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Int8Slice(assignmentVar *[]int8, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // IP adds a new net.IP flag.
-func IP(assignmentVar *net.IP, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func IP(assignmentVar *net.IP, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // IPSlice adds a new int8 slice flag.
 // Specify the flag multiple times to fill the slice.
-func IPSlice(assignmentVar *[]net.IP, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func IPSlice(assignmentVar *[]net.IP, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // HardwareAddr adds a new net.HardwareAddr flag.
-func HardwareAddr(assignmentVar *net.HardwareAddr, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func HardwareAddr(assignmentVar *net.HardwareAddr, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // HardwareAddrSlice adds a new net.HardwareAddr slice flag.
 // Specify the flag multiple times to fill the slice.
-func HardwareAddrSlice(assignmentVar *[]net.HardwareAddr, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func HardwareAddrSlice(assignmentVar *[]net.HardwareAddr, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // IPMask adds a new net.IPMask flag. IPv4 Only.
-func IPMask(assignmentVar *net.IPMask, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func IPMask(assignmentVar *net.IPMask, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // IPMaskSlice adds a new net.HardwareAddr slice flag. IPv4 only.
 // Specify the flag multiple times to fill the slice.
-func IPMaskSlice(assignmentVar *[]net.IPMask, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func IPMaskSlice(assignmentVar *[]net.IPMask, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Time adds a new time.Time flag. Supports RFC3339/RFC3339Nano, RFC1123, and unix seconds.
-func Time(assignmentVar *time.Time, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Time(assignmentVar *time.Time, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // URL adds a new url.URL flag.
-func URL(assignmentVar *url.URL, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func URL(assignmentVar *url.URL, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // IPNet adds a new net.IPNet flag parsed from CIDR.
-func IPNet(assignmentVar *net.IPNet, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func IPNet(assignmentVar *net.IPNet, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // TCPAddr adds a new net.TCPAddr flag parsed from host:port.
-func TCPAddr(assignmentVar *net.TCPAddr, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func TCPAddr(assignmentVar *net.TCPAddr, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // UDPAddr adds a new net.UDPAddr flag parsed from host:port.
-func UDPAddr(assignmentVar *net.UDPAddr, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func UDPAddr(assignmentVar *net.UDPAddr, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // FileMode adds a new os.FileMode flag parsed from octal/decimal (base auto-detected).
-func FileMode(assignmentVar *os.FileMode, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func FileMode(assignmentVar *os.FileMode, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Regexp adds a new regexp.Regexp flag.
-func Regexp(assignmentVar *regexp.Regexp, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Regexp(assignmentVar *regexp.Regexp, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Location adds a new time.Location flag.
-func Location(assignmentVar *time.Location, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Location(assignmentVar *time.Location, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Month adds a new time.Month flag.
-func Month(assignmentVar *time.Month, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Month(assignmentVar *time.Month, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // Weekday adds a new time.Weekday flag.
-func Weekday(assignmentVar *time.Weekday, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func Weekday(assignmentVar *time.Weekday, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // BigInt adds a new big.Int flag.
-func BigInt(assignmentVar *big.Int, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func BigInt(assignmentVar *big.Int, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // BigRat adds a new big.Rat flag.
-func BigRat(assignmentVar *big.Rat, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func BigRat(assignmentVar *big.Rat, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // NetipAddr adds a new netip.Addr flag.
-func NetipAddr(assignmentVar *netip.Addr, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func NetipAddr(assignmentVar *netip.Addr, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // NetipPrefix adds a new netip.Prefix flag.
-func NetipPrefix(assignmentVar *netip.Prefix, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func NetipPrefix(assignmentVar *netip.Prefix, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
 }
 
 // NetipAddrPort adds a new netip.AddrPort flag.
-func NetipAddrPort(assignmentVar *netip.AddrPort, shortName string, longName string, description string) {
-	DefaultParser.add(assignmentVar, shortName, longName, description)
+func NetipAddrPort(assignmentVar *netip.AddrPort, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
+}
+
+// StringToString adds a new map of strings flag.  Specify the flag multiple
+// times or separate pairs with commas to fill the map.
+func StringToString(assignmentVar *map[string]string, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
+}
+
+// StringMap adds a new map of strings flag.  It is the same as StringToString.
+func StringMap(assignmentVar *map[string]string, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
+}
+
+// StringToInt adds a new map of strings to ints flag.  Specify the flag
+// multiple times or separate pairs with commas to fill the map.
+func StringToInt(assignmentVar *map[string]int, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
+}
+
+// Var adds a new flag backed by a custom Value implementation.
+func Var(assignmentVar Value, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
+}
+
+// TextVar adds a new flag backed by a type implementing encoding.TextUnmarshaler.
+func TextVar(assignmentVar encoding.TextUnmarshaler, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
+}
+
+// FlagValue adds a new flag backed by a type implementing the standard
+// library's flag.Value interface.
+func FlagValue(assignmentVar flag.Value, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add(assignmentVar, shortName, longName, description)
+}
+
+// Count adds a new counter flag that is incremented every time it is used.
+func Count(assignmentVar *int, shortName string, longName string, description string) *Flag {
+	return DefaultParser.add((*Counter)(assignmentVar), shortName, longName, description)
+}
+
+// Enum adds a new string flag that only accepts one of the allowed values.
+func Enum(assignmentVar *string, shortName string, longName string, description string, allowed []string) *Flag {
+	return DefaultParser.add(&enumValue{value: assignmentVar, allowed: allowed}, shortName, longName, description)
+}
+
+// EnumSlice adds a new slice of strings flag that only accepts the allowed
+// values.  Specify the flag multiple times to fill the slice.
+func EnumSlice(assignmentVar *[]string, shortName string, longName string, description string, allowed []string) *Flag {
+	return DefaultParser.add(&enumSliceValue{values: assignmentVar, allowed: allowed}, shortName, longName, description)
 }
 
 // AttachSubcommand adds a subcommand for parsing
@@ -410,20 +460,11 @@ func SetName(name string) {
 // ShowHelpAndExit shows parser help and exits with status code 2
 func ShowHelpAndExit(message string) {
 	ShowHelp(message)
-	exitOrPanic(2)
+	DefaultParser.exit(2)
 }
 
-// PanicInsteadOfExit is used when running tests
-var PanicInsteadOfExit bool
-
-// exitOrPanic panics instead of calling os.Exit so that tests can catch
-// more failures
-func exitOrPanic(code int) {
-	if PanicInsteadOfExit {
-		panic("Panic instead of exit with code: " + strconv.Itoa(code))
-	}
-	os.Exit(code)
-}
+// osExit exits the program.  Tests replace it to catch exits.
+var osExit = os.Exit
 
 // ShowHelpOnUnexpectedEnable enables the ShowHelpOnUnexpected behavior on the
 // default parser.  This causes unknown inputs to error out.
@@ -439,10 +480,16 @@ func ShowHelpOnUnexpectedDisable() {
 
 // AddPositionalValue adds a positional value to the main parser at the global
 // context
-func AddPositionalValue(assignmentVar *string, name string, relativePosition int, required bool, description string) {
+func AddPositionalValue(assignmentVar interface{}, name string, relativePosition int, required bool, description string) {
 	DefaultParser.AddPositionalValue(assignmentVar, name, relativePosition, required, description)
 }
 
+// AddPositionalSlice adds a variadic positional value to the main parser
+// that collects every positional value at and after relativePosition.
+func AddPositionalSlice(assignmentVar interface{}, name string, relativePosition int, min int, max int, description string) {
+	DefaultParser.AddPositionalSlice(assignmentVar, name, relativePosition, min, max, description)
+}
+
 // debugPrint prints if debugging is enabled
 func debugPrint(i ...interface{}) {
 	if DebugMode {
@@ -15,6 +15,7 @@ type Help struct {
 	Positionals    []HelpPositional
 	Flags          []HelpFlag
 	GlobalFlags    []HelpFlag
+	FlagGroups     []HelpFlagGroup
 	UsageString    string
 	CommandName    string
 	PrependMessage string
@@ -41,6 +42,7 @@ type HelpPositional struct {
 	Required     bool
 	Position     int
 	DefaultValue string
+	Constraint   string
 	Spacer       string
 }
 
@@ -50,10 +52,21 @@ type HelpFlag struct {
 	LongName     string
 	Description  string
 	DefaultValue string
+	EnvVar       string
+	Required     bool
+	Repeatable   bool
+	Choices      []string
+	Constraint   string
 	ShortDisplay string
 	LongDisplay  string
 }
 
+// HelpFlagGroup is used to template flag group Help output
+type HelpFlagGroup struct {
+	Kind  string
+	Flags []string
+}
+
 // ExtractValues extracts Help template values from a subcommand and its parent
 // parser. The parser is required in order to detect default flag settings
 // for help and version output.
@@ -91,7 +104,7 @@ func (h *Help) ExtractValues(p *Parser, message string) {
 
 	// subcommands    []HelpSubcommand
 	for _, cmd := range ctx.Subcommands {
-		if cmd.Hidden {
+		if cmd.hidden() {
 			continue
 		}
 		newHelpSubcommand := HelpSubcommand{
@@ -130,6 +143,7 @@ func (h *Help) ExtractValues(p *Parser, message string) {
 			Description:  pos.Description,
 			Required:     pos.Required,
 			DefaultValue: pos.defaultValue,
+			Constraint:   pos.Constraint,
 			Spacer:       makeSpacer(pos.Name, maxLength),
 		}
 		h.Positionals = append(h.Positionals, newHelpPositional)
@@ -166,13 +180,19 @@ func (h *Help) ExtractValues(p *Parser, message string) {
 	}
 
 	// go through every flag in the subcommand and add it to help output
-	h.parseFlagsToHelpFlags(ctx.Flags, &h.Flags)
+	h.parseFlagsToHelpFlags(p, p.subcommandPath(ctx), ctx.Flags, &h.Flags)
 
 	// go through every flag in the parent parser and add it to help output
 	if isRootContext {
-		h.parseFlagsToHelpFlags(p.Flags, &h.Flags)
+		h.parseFlagsToHelpFlags(p, nil, p.Flags, &h.Flags)
 	} else {
-		h.parseFlagsToHelpFlags(p.Flags, &h.GlobalFlags)
+		h.parseFlagsToHelpFlags(p, nil, p.Flags, &h.GlobalFlags)
+	}
+
+	// add the flag groups of the subcommand and, when nested, the parent parser
+	h.parseFlagGroupsToHelpFlagGroups(ctx)
+	if !isRootContext {
+		h.parseFlagGroupsToHelpFlagGroups(&p.Subcommand)
 	}
 
 	// Optionally sort flags alphabetically by long name (fallback to short name)
@@ -218,14 +238,18 @@ func (h *Help) ExtractValues(p *Parser, message string) {
 		if pos.Hidden {
 			continue
 		}
+		name := pos.Name
+		if pos.variadic {
+			name += "..."
+		}
 		if len(commandsByPosition[pos.Position]) > 0 {
-			commandsByPosition[pos.Position] = commandsByPosition[pos.Position] + "|" + pos.Name
+			commandsByPosition[pos.Position] = commandsByPosition[pos.Position] + "|" + name
 		} else {
-			commandsByPosition[pos.Position] = pos.Name
+			commandsByPosition[pos.Position] = name
 		}
 	}
 	for _, cmd := range ctx.Subcommands {
-		if cmd.Hidden {
+		if cmd.hidden() {
 			continue
 		}
 		if len(commandsByPosition[cmd.Position]) > 0 {
@@ -267,10 +291,11 @@ func (h *Help) ExtractValues(p *Parser, message string) {
 }
 
 // parseFlagsToHelpFlags parses the specified slice of flags into
-// help flags on the the calling help command
-func (h *Help) parseFlagsToHelpFlags(flags []*Flag, dest *[]HelpFlag) {
+// help flags on the the calling help command.  The subcommand path is used
+// to derive the environment variable bound to each flag.
+func (h *Help) parseFlagsToHelpFlags(p *Parser, path []*Subcommand, flags []*Flag, dest *[]HelpFlag) {
 	for _, f := range flags {
-		if f.Hidden {
+		if f.hidden() {
 			continue
 		}
 
@@ -297,17 +322,40 @@ func (h *Help) parseFlagsToHelpFlags(flags []*Flag, dest *[]HelpFlag) {
 				defaultValue = ""
 			}
 		}
+		if isBoolValue(f.AssignmentVar) && defaultValue == "false" {
+			defaultValue = ""
+		}
+		// counters start at zero, so only show a default they were given
+		if f.isCounter() && defaultValue == "0" {
+			defaultValue = ""
+		}
 
 		newHelpFlag := HelpFlag{
 			ShortName:    f.ShortName,
 			LongName:     f.LongName,
 			Description:  f.Description,
 			DefaultValue: defaultValue,
+			EnvVar:       p.envVarName(path, f),
+			Required:     f.Required,
+			Repeatable:   f.isCounter(),
+			Choices:      f.choices(),
+			Constraint:   f.Constraint,
 		}
 		h.addFlagToSlice(dest, newHelpFlag)
 	}
 }
 
+// parseFlagGroupsToHelpFlagGroups adds the flag groups of the specified
+// subcommand to the help output.
+func (h *Help) parseFlagGroupsToHelpFlagGroups(sc *Subcommand) {
+	for _, g := range sc.flagGroups {
+		h.FlagGroups = append(h.FlagGroups, HelpFlagGroup{
+			Kind:  g.Kind.String(),
+			Flags: flagDisplayNames(sc.groupFlags(g)),
+		})
+	}
+}
+
 // addFlagToSlice adds a flag to the provided slice if it does not exist already.
 func (h *Help) addFlagToSlice(dest *[]HelpFlag, f HelpFlag) {
 	for _, existingFlag := range *dest {
@@ -471,6 +519,9 @@ func (h *Help) composeLines() {
 			if pos.Description != "" {
 				line += " " + pos.Description
 			}
+			if pos.Constraint != "" {
+				line += " (" + pos.Constraint + ")"
+			}
 			if pos.DefaultValue != "" {
 				line += " (default: " + pos.DefaultValue + ")"
 			} else if pos.Required {
@@ -502,20 +553,7 @@ func (h *Help) composeLines() {
 	if len(h.Flags) > 0 {
 		section := []string{"  Flags:"}
 		for _, flag := range h.Flags {
-			line := "    " + flag.ShortDisplay + flag.LongDisplay
-			descAdded := false
-			if flag.Description != "" {
-				line += flag.Description
-				descAdded = true
-			}
-			if flag.DefaultValue != "" {
-				if descAdded {
-					line += " (default: " + flag.DefaultValue + ")"
-				} else {
-					line += "(default: " + flag.DefaultValue + ")"
-				}
-			}
-			section = append(section, line)
+			section = append(section, flagHelpLine(flag))
 		}
 		appendSection(section)
 	}
@@ -523,20 +561,15 @@ func (h *Help) composeLines() {
 	if len(h.GlobalFlags) > 0 {
 		section := []string{"  Global Flags:"}
 		for _, flag := range h.GlobalFlags {
-			line := "    " + flag.ShortDisplay + flag.LongDisplay
-			descAdded := false
-			if flag.Description != "" {
-				line += flag.Description
-				descAdded = true
-			}
-			if flag.DefaultValue != "" {
-				if descAdded {
-					line += " (default: " + flag.DefaultValue + ")"
-				} else {
-					line += "(default: " + flag.DefaultValue + ")"
-				}
-			}
-			section = append(section, line)
+			section = append(section, flagHelpLine(flag))
+		}
+		appendSection(section)
+	}
+
+	if len(h.FlagGroups) > 0 {
+		section := []string{"  Flag Groups:"}
+		for _, group := range h.FlagGroups {
+			section = append(section, "    "+group.Kind+": "+strings.Join(group.Flags, ", "))
 		}
 		appendSection(section)
 	}
@@ -566,6 +599,39 @@ func (h *Help) composeLines() {
 	h.Lines = lines
 }
 
+// flagHelpLine formats a single flag for the flag sections of the help
+// output, followed by its default value, environment variable and whether
+// it is required.
+func flagHelpLine(flag HelpFlag) string {
+	line := "    " + flag.ShortDisplay + flag.LongDisplay
+	var notes []string
+	if flag.DefaultValue != "" {
+		notes = append(notes, "(default: "+flag.DefaultValue+")")
+	}
+	if len(flag.Choices) > 0 {
+		notes = append(notes, "(choices: "+strings.Join(flag.Choices, ", ")+")")
+	}
+	if flag.Constraint != "" {
+		notes = append(notes, "("+flag.Constraint+")")
+	}
+	if flag.EnvVar != "" {
+		notes = append(notes, "(env: "+flag.EnvVar+")")
+	}
+	if flag.Repeatable {
+		notes = append(notes, "(repeatable)")
+	}
+	if flag.Required {
+		notes = append(notes, "(Required)")
+	}
+	if flag.Description != "" {
+		line += flag.Description
+		if len(notes) > 0 {
+			line += " "
+		}
+	}
+	return line + strings.Join(notes, " ")
+}
+
 func splitLines(input string) []string {
 	if input == "" {
 		return nil
@@ -148,7 +148,6 @@ func TestHelpWithMissingSCName(t *testing.T) {
 		}
 	}()
 	flaggy.ResetParser()
-	flaggy.PanicInsteadOfExit = true
 	sc := flaggy.NewSubcommand("")
 	sc.ShortName = "sn"
 	flaggy.AttachSubcommand(sc, 1)
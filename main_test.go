@@ -8,7 +8,6 @@ import (
 )
 
 func TestMain(m *testing.M) {
-	flaggy.PanicInsteadOfExit = true
 	// flaggy.DebugMode = true
 	os.Exit(m.Run())
 }
@@ -43,7 +42,6 @@ func TestSetName(t *testing.T) {
 }
 
 func TestShowHelpAndExit(t *testing.T) {
-	flaggy.PanicInsteadOfExit = true
 	defer func() {
 		r := recover()
 		if r == nil {
@@ -0,0 +1,82 @@
+package flaggy_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestStringToString(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	labels := map[string]string{"owner": "ops"}
+	var set map[string]string
+	p.StringToString(&labels, "l", "label", "Labels")
+	p.StringMap(&set, "", "set", "Values to set")
+	if err := p.ParseArgs([]string{"--label", "env=prod", "-l=team=infra", "--set", "a=1,b=x=y"}); err != nil {
+		t.Fatal(err)
+	}
+	wantLabels := map[string]string{"owner": "ops", "env": "prod", "team": "infra"}
+	if !reflect.DeepEqual(labels, wantLabels) {
+		t.Fatalf("expected %v, got %v", wantLabels, labels)
+	}
+	wantSet := map[string]string{"a": "1", "b": "x=y"}
+	if !reflect.DeepEqual(set, wantSet) {
+		t.Fatalf("expected %v, got %v", wantSet, set)
+	}
+}
+
+func TestStringToInt(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var limits map[string]int
+	p.StringToInt(&limits, "", "limit", "Limits")
+	if err := p.ParseArgs([]string{"--limit", "cpu=2,mem=512", "--limit=cpu=4"}); err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]int{"cpu": 4, "mem": 512}
+	if !reflect.DeepEqual(limits, want) {
+		t.Fatalf("expected %v, got %v", want, limits)
+	}
+}
+
+func TestMapMalformedPairs(t *testing.T) {
+	tests := [][]string{
+		{"--label", "env"},
+		{"--label", "=prod"},
+		{"--label", "a=1,"},
+		{"--limit", "cpu=lots"},
+	}
+	for _, args := range tests {
+		p := flaggy.NewParser("app")
+		p.ErrorHandling = flaggy.ContinueOnError
+		var labels map[string]string
+		var limits map[string]int
+		p.StringToString(&labels, "", "label", "Labels")
+		p.StringToInt(&limits, "", "limit", "Limits")
+		err := p.ParseArgs(args)
+		var conversion *flaggy.ConversionError
+		if !errors.As(err, &conversion) {
+			t.Fatalf("expected *ConversionError for %v, got %T: %v", args, err, err)
+		}
+		if len(labels) != 0 || len(limits) != 0 {
+			t.Fatalf("expected no pairs to be assigned for %v, got %v %v", args, labels, limits)
+		}
+	}
+}
+
+func TestMapDefaultSortedInHelp(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	labels := map[string]string{"team": "infra", "env": "prod", "app": "web"}
+	p.StringToString(&labels, "l", "label", "Labels")
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	out := strings.Join(h.Lines, "\n")
+	if !strings.Contains(out, "(default: app=web,env=prod,team=infra)") {
+		t.Fatalf("expected a sorted default in help:\n%s", out)
+	}
+}
@@ -3,6 +3,7 @@ package flaggy
 import (
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
@@ -28,6 +29,17 @@ type Parser struct {
 	ShowCompletion             bool               // indicates that bash and zsh completion output is possible
 	SortFlags                  bool               // when true, help output flags are sorted alphabetically
 	SortFlagsReverse           bool               // when true with SortFlags, sort order is reversed (Z..A)
+	ErrorHandling              ErrorHandling      // how parsing errors are handled.  Defaults to ExitOnError
+	EnvPrefix                  string             // when set, flags are bound to environment variables named PREFIX_SUBCOMMAND_FLAG
+	ConfigFlag                 string             // when set, a root flag with this long name loads values from a config file
+	configFile                 string             // the config file path supplied with the ConfigFlag
+	configEntries              []configEntry      // config file values waiting to be applied after parsing
+	configApplied              bool               // indicates config file values have been applied by ParseArgs
+	SuggestionDistance         int                // the maximum edit distance for "Did you mean" suggestions.  Zero disables them
+	POSIXShortFlags            bool               // when true, single dash arguments like -xzvf are split into single letter short flags
+	SliceDelimiter             string             // when set, values of slice flags without their own Delimiter are split on this character
+	ReplaceSliceDefaults       bool               // when true, the first value supplied to any slice flag replaces its default contents
+	WarningWriter              io.Writer          // where deprecation warnings are written.  Defaults to os.Stderr
 }
 
 // supportedCompletionShells lists every shell that can receive generated completion output.
@@ -63,6 +75,8 @@ func NewParser(name string) *Parser {
 	p.ShowHelpWithHFlag = true
 	p.ShowVersionWithVersionFlag = true
 	p.ShowCompletion = true
+	p.ErrorHandling = ExitOnError
+	p.SuggestionDistance = defaultSuggestionDistance
 	p.SortFlags = false
 	p.SortFlagsReverse = false
 	p.SetHelpTemplate(DefaultHelpTemplate)
@@ -102,75 +116,148 @@ func (p *Parser) SortFlagsByLongNameReversed() {
 }
 
 // ParseArgs parses as if the passed args were the os.Args, but without the
-// binary at the 0 position in the array.  An error is returned if there
-// is a low level issue converting flags to their proper type.  No error
-// is returned for invalid arguments or missing require subcommands.
+// binary at the 0 position in the array.  Invalid arguments produce an
+// *UnknownArgumentError, *MissingValueError, or *RequiredPositionalError
+// and values that can not be converted produce a *ConversionError.  How
+// those errors are handled depends on the parser's ErrorHandling.
 func (p *Parser) ParseArgs(args []string) error {
 	if p.parsed {
 		return errors.New("Parser.Parse() called twice on parser with name: " + " " + p.Name + " " + p.ShortName)
 	}
 	p.parsed = true
 
+	p.registerConfigFlag()
+	p.resolveSliceOptions()
+
 	// Handle shell completion before any parsing to avoid unknown-argument exits.
 	if p.ShowCompletion {
+		// the generated completion scripts call back for candidates
+		if len(args) >= 1 && args[0] == completeCommand {
+			p.writeCandidates(os.Stdout, args[1:])
+			return p.stopParsing(0, ErrCompletion)
+		}
+
 		if len(args) >= 1 && strings.EqualFold(args[0], "completion") {
 			// no shell provided
 			if len(args) < 2 {
 				fmt.Fprintf(os.Stderr, "Please specify a shell for completion. Supported shells: %s\n", completionShellList())
-				exitOrPanic(2)
+				return p.stopParsing(2, &ExitError{Code: 2, Err: errors.New("no shell specified for completion")})
+			}
+
+			// install or remove the script for the user's shell
+			if action := strings.ToLower(args[1]); action == "install" || action == "uninstall" {
+				return p.runCompletionInstall(action, args[2:])
 			}
 
 			shell := strings.ToLower(args[1])
 			if isSupportedCompletionShell(shell) {
 				p.Completion(shell)
-				exitOrPanic(0)
+				return p.stopParsing(0, ErrCompletion)
 			}
 			fmt.Fprintf(os.Stderr, "Unsupported shell specified for completion: %s\nSupported shells: %s\n", args[1], completionShellList())
-			exitOrPanic(2)
+			return p.stopParsing(2, &ExitError{Code: 2, Err: errors.New("unsupported shell specified for completion: " + args[1])})
 		}
 	}
 
+	// split clustered short flags like -xzvf before anything else sees them
+	if p.POSIXShortFlags {
+		args = p.expandPOSIXShortFlags(args)
+	} else {
+		args = p.expandCounterClusters(args)
+	}
+
 	debugPrint("Kicking off parsing with args:", args)
 	err := p.parse(p, args)
 	if err != nil {
-		return err
+		// point conversion errors at the argument that supplied the value
+		var conversion *ConversionError
+		if errors.As(err, &conversion) && !conversion.Positional {
+			conversion.Position = locateFlagArg(args, conversion.Flag, conversion.Value)
+		}
+		var validation *ValidationError
+		if errors.As(err, &validation) && !validation.Positional {
+			validation.Position = locateFlagArg(args, validation.Flag, validation.Value)
+		}
+		return p.handleError(err)
 	}
 
+	// fill in any flags that were not supplied on the command line from
+	// their bound environment variables
+	err = p.applyEnvironment()
+	if err != nil {
+		return p.handleError(err)
+	}
+
+	// then fill in anything still unset from config files
+	err = p.applyConfig()
+	if err != nil {
+		return p.handleError(err)
+	}
+
+	// let users know when they are relying on deprecated names
+	p.warnDeprecated()
+
 	// if we are set to exit on unexpected args, look for those here
 	if p.ShowHelpOnUnexpected {
 		parsedValues := p.findAllParsedValues()
 		debugPrint("parsedValues:", parsedValues)
 		argsNotParsed := findArgsNotInParsedValues(args, parsedValues)
 		if len(argsNotParsed) > 0 {
-			// flatten out unused args for our error message
-			var argsNotParsedFlat string
-			for _, a := range argsNotParsed {
-				argsNotParsedFlat = argsNotParsedFlat + " " + a
+			unknown := &UnknownArgumentError{
+				Subcommand: p.subcommandContext.Name,
+				Arguments:  argsNotParsed,
+			}
+			for _, arg := range argsNotParsed {
+				if suggestions := p.suggestFlags(arg); len(suggestions) > 0 {
+					if unknown.Suggestions == nil {
+						unknown.Suggestions = make(map[string][]string)
+					}
+					unknown.Suggestions[arg] = suggestions
+				}
 			}
-			p.ShowHelpAndExit("Unknown arguments supplied: " + argsNotParsedFlat)
+			return p.handleError(unknown)
 		}
 	}
 
+	// every source of values has been applied, so required flags and flag
+	// groups can now be checked
+	err = errors.Join(p.checkRequiredFlags(), p.checkFlagGroups())
+	if err != nil {
+		return p.handleError(err)
+	}
+
+	return nil
+}
+
+// checkRequiredFlags reports every required flag on the root parser and the
+// used subcommands that was not given a value.  Required flags on
+// subcommands that were not used are not enforced.
+func (p *Parser) checkRequiredFlags() error {
+	var missing []string
+	subcommands := append([]*Subcommand{&p.Subcommand}, p.usedSubcommandPath()...)
+	for _, sc := range subcommands {
+		for _, f := range sc.Flags {
+			if !f.Required || f.Changed() {
+				continue
+			}
+			missing = append(missing, f.displayName())
+		}
+	}
+	if len(missing) > 0 {
+		return &RequiredFlagError{Flags: missing}
+	}
 	return nil
 }
 
 // Completion takes in a shell type and outputs the completion script for
 // that shell.
 func (p *Parser) Completion(completionType string) {
-	switch strings.ToLower(completionType) {
-	case "bash":
-		fmt.Print(GenerateBashCompletion(p))
-	case "zsh":
-		fmt.Print(GenerateZshCompletion(p))
-	case "fish":
-		fmt.Print(GenerateFishCompletion(p))
-	case "powershell":
-		fmt.Print(GeneratePowerShellCompletion(p))
-	case "nushell":
-		fmt.Print(GenerateNushellCompletion(p))
-	default:
+	script := completionScript(p, completionType)
+	if script == "" {
 		fmt.Fprintf(os.Stderr, "Unsupported shell specified for completion: %s\nSupported shells: %s\n", completionType, completionShellList())
+		return
 	}
+	fmt.Print(script)
 }
 
 // findArgsNotInParsedValues finds arguments not used in parsed values.  The
@@ -281,8 +368,13 @@ func findArgsNotInParsedValues(args []string, parsedValues []parsedValue) []stri
 
 // ShowVersionAndExit shows the version of this parser
 func (p *Parser) ShowVersionAndExit() {
+	p.showVersion()
+	p.exit(0)
+}
+
+// showVersion prints the version of this parser
+func (p *Parser) showVersion() {
 	fmt.Println("Version:", p.Version)
-	exitOrPanic(0)
 }
 
 // SetHelpTemplate sets the go template this parser will use when rendering
@@ -311,7 +403,16 @@ func (p *Parser) ShowHelp() {
 // ShowHelpAndExit shows parser help and exits with status code 2
 func (p *Parser) ShowHelpAndExit(message string) {
 	p.ShowHelpWithMessage(message)
-	exitOrPanic(2)
+	p.exit(2)
+}
+
+// exit exits the program with the supplied status code.  Parsers that panic
+// on errors panic instead, so that tests can recover.
+func (p *Parser) exit(code int) {
+	if p.ErrorHandling == PanicOnError {
+		panic("Panic instead of exit with code: " + strconv.Itoa(code))
+	}
+	osExit(code)
 }
 
 // ShowHelpWithMessage shows the Help for this parser with an optional string error
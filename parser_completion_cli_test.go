@@ -1,6 +1,7 @@
 package flaggy_test
 
 import (
+	"errors"
 	"io"
 	"os"
 	"strings"
@@ -10,8 +11,15 @@ import (
 )
 
 // runParserWithArgs executes a new parser using the provided os.Args tail while capturing
-// stdout, stderr, and the panic payload triggered by exitOrPanic so tests can assert behavior.
-func runParserWithArgs(t *testing.T, args []string) (string, string, any) {
+// stdout, stderr, and the error returned in place of exiting so tests can assert behavior.
+func runParserWithArgs(t *testing.T, args []string) (string, string, error) {
+	t.Helper()
+	return runParser(t, flaggy.NewParser("starfleet"), args)
+}
+
+// runParser executes the supplied parser with the provided os.Args tail and captures its
+// output the same way as runParserWithArgs.
+func runParser(t *testing.T, parser *flaggy.Parser, args []string) (string, string, error) {
 	t.Helper()
 
 	originalArgs := os.Args
@@ -20,8 +28,6 @@ func runParserWithArgs(t *testing.T, args []string) (string, string, any) {
 		os.Args = originalArgs
 	}()
 
-	parser := flaggy.NewParser("starfleet")
-
 	originalStdout := os.Stdout
 	stdoutReader, stdoutWriter, err := os.Pipe()
 	if err != nil {
@@ -41,19 +47,8 @@ func runParserWithArgs(t *testing.T, args []string) (string, string, any) {
 		os.Stderr = originalStderr
 	}()
 
-	originalPanicSetting := flaggy.PanicInsteadOfExit
-	flaggy.PanicInsteadOfExit = true
-	defer func() {
-		flaggy.PanicInsteadOfExit = originalPanicSetting
-	}()
-
-	var recovered any
-	func() {
-		defer func() {
-			recovered = recover()
-		}()
-		_ = parser.Parse()
-	}()
+	parser.ErrorHandling = flaggy.ContinueOnError
+	parseErr := parser.Parse()
 
 	stdoutWriter.Close()
 	stderrWriter.Close()
@@ -70,19 +65,18 @@ func runParserWithArgs(t *testing.T, args []string) (string, string, any) {
 	stdoutReader.Close()
 	stderrReader.Close()
 
-	return string(stdoutBytes), string(stderrBytes), recovered
+	return string(stdoutBytes), string(stderrBytes), parseErr
 }
 
 // TestParseCompletionRequiresShell ensures that invoking the completion command without a shell
 // prints guidance that lists every supported shell so users know their options.
 func TestParseCompletionRequiresShell(t *testing.T) {
-	_, stderr, recovered := runParserWithArgs(t, []string{"completion"})
-	if recovered == nil {
-		t.Fatalf("expected parser to exit when no shell provided")
+	_, stderr, err := runParserWithArgs(t, []string{"completion"})
+	if err == nil {
+		t.Fatalf("expected parser to stop when no shell provided")
 	}
-	expectedExit := "Panic instead of exit with code: 2"
-	if recovered != expectedExit {
-		t.Fatalf("expected exit code 2 when shell missing: %v", recovered)
+	if code := flaggy.ExitCode(err); code != 2 {
+		t.Fatalf("expected exit code 2 when shell missing: %d %v", code, err)
 	}
 	want := "Supported shells: bash zsh fish powershell nushell"
 	if !strings.Contains(stderr, want) {
@@ -105,13 +99,9 @@ func TestParseCompletionSupportsAllShells(t *testing.T) {
 	}
 
 	for _, tc := range cases {
-		stdout, stderr, recovered := runParserWithArgs(t, []string{"completion", tc.shell})
-		if recovered == nil {
-			t.Fatalf("expected parser to exit after writing %s completion", tc.shell)
-		}
-		expectedExit := "Panic instead of exit with code: 0"
-		if recovered != expectedExit {
-			t.Fatalf("expected exit code 0 for %s completion: %v", tc.shell, recovered)
+		stdout, stderr, err := runParserWithArgs(t, []string{"completion", tc.shell})
+		if !errors.Is(err, flaggy.ErrCompletion) {
+			t.Fatalf("expected parser to stop after writing %s completion: %v", tc.shell, err)
 		}
 		if len(stderr) > 0 {
 			t.Fatalf("expected stderr to be empty for %s completion: %s", tc.shell, stderr)
@@ -1,14 +1,122 @@
 package flaggy
 
+import "reflect"
+
 // PositionalValue represents a value which is determined by its position
 // relative to where a subcommand was detected.
 type PositionalValue struct {
-	Name          string // used in documentation only
-	Description   string
-	AssignmentVar *string // the var that will get this variable
-	Position      int     // the position, not including switches, of this variable
-	Required      bool    // this subcommand must always be specified
-	Found         bool    // was this positional found during parsing?
-	Hidden        bool    // indicates this positional value should be hidden from help
-	defaultValue  string  // used for help output
+	Name           string // used in documentation only
+	Description    string
+	AssignmentVar  interface{}                   // the var that will get this variable.  Supports the same types as flags
+	Position       int                           // the position, not including switches, of this variable
+	Required       bool                          // this subcommand must always be specified
+	Found          bool                          // was this positional found during parsing?
+	Hidden         bool                          // indicates this positional value should be hidden from help
+	Min            int                           // the fewest values a variadic positional accepts
+	Max            int                           // the most values a variadic positional accepts, or 0 for no limit
+	Validate       func(value interface{}) error // when set, called with the positional's value after each assignment.  Returned errors reject the value and leave the variable unchanged
+	Constraint     string                        // describes the values Validate accepts in help output
+	CompletionFunc CompletionFunc                // when set, returns the candidates offered by shell completion for this position
+	defaultValue   string                        // used for help output
+	parsed         bool                          // indicates that the default value has been captured
+	variadic       bool                          // indicates this positional collects every remaining value
+	count          int                           // the number of values assigned during parsing
+}
+
+// coversDepth reports whether this positional receives values at the
+// supplied relative depth.  Variadic positionals cover every depth at or
+// after their position.
+func (pos *PositionalValue) coversDepth(depth int) bool {
+	if pos.variadic {
+		return depth >= pos.Position
+	}
+	return depth == pos.Position
+}
+
+// positionalForDepth finds the positional value that receives the token at
+// the supplied relative depth when count positional tokens were supplied in
+// total.  A variadic positional takes every token from its position onward
+// except for the ones needed by the fixed positionals that follow it.
+func (sc *Subcommand) positionalForDepth(depth int, count int) *PositionalValue {
+	var variadic *PositionalValue
+	for _, pos := range sc.PositionalFlags {
+		if pos.variadic {
+			variadic = pos
+		}
+	}
+	if variadic == nil || depth < variadic.Position {
+		for _, pos := range sc.PositionalFlags {
+			if pos.Position == depth {
+				return pos
+			}
+		}
+		return nil
+	}
+
+	var trailing int
+	for _, pos := range sc.PositionalFlags {
+		if pos.Position > variadic.Position {
+			trailing++
+		}
+	}
+	lastVariadicDepth := max(count-trailing, variadic.Position-1)
+	if depth <= lastVariadicDepth {
+		return variadic
+	}
+	position := variadic.Position + depth - lastVariadicDepth
+	for _, pos := range sc.PositionalFlags {
+		if pos.Position == position {
+			return pos
+		}
+	}
+	return nil
+}
+
+// positionalDefaultValue renders the current value of the positional's
+// assignment variable for help output.  Zero values are not shown.
+func positionalDefaultValue(assignmentVar interface{}) string {
+	if v := reflect.ValueOf(assignmentVar); v.Kind() == reflect.Pointer && !v.IsNil() && v.Elem().IsZero() {
+		return ""
+	}
+	str, _, _ := valueAsString(assignmentVar)
+	return str
+}
+
+// assign converts the supplied value to the type of the assignment variable
+// and assigns it.  The default value is remembered for help output first,
+// and the default contents of a variadic positional are then dropped so the
+// supplied values replace them.  Rejected values leave the assignment
+// variable as it was.
+func (pos *PositionalValue) assign(value string, relativePosition int) error {
+	restore := saveValue(pos.AssignmentVar)
+	if !pos.parsed {
+		pos.parsed = true
+		pos.defaultValue = positionalDefaultValue(pos.AssignmentVar)
+		if pos.variadic {
+			resetSlice(pos.AssignmentVar)
+		}
+	}
+	if _, err := assignValue(pos.AssignmentVar, value); err != nil {
+		restore()
+		return &ConversionError{Flag: pos.Name, Value: value, Position: relativePosition, Positional: true, Err: err}
+	}
+	if err := runValidate(pos.Validate, pos.Name, value, relativePosition, true, pos.AssignmentVar); err != nil {
+		restore()
+		return err
+	}
+	pos.count++
+	pos.Found = true
+	return nil
+}
+
+// checkCount reports a variadic positional that received fewer than Min or
+// more than Max values.
+func (pos *PositionalValue) checkCount(subcommand string) error {
+	if !pos.variadic {
+		return nil
+	}
+	if pos.count < pos.Min || (pos.Max > 0 && pos.count > pos.Max) {
+		return &PositionalCountError{Subcommand: subcommand, Name: pos.Name, Min: pos.Min, Max: pos.Max, Count: pos.count}
+	}
+	return nil
 }
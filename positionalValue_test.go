@@ -0,0 +1,197 @@
+package flaggy_test
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestTypedPositionalValues(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	sub := flaggy.NewSubcommand("ping")
+	var count int
+	var timeout time.Duration
+	var addr net.IP
+	sub.AddPositionalValue(&addr, "addr", 1, true, "address to ping")
+	sub.AddPositionalValue(&count, "count", 2, false, "number of pings")
+	sub.AddPositionalValue(&timeout, "timeout", 3, false, "time to wait")
+	p.AttachSubcommand(sub, 1)
+
+	if err := p.ParseArgs([]string{"ping", "10.0.0.1", "3", "250ms"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if addr.String() != "10.0.0.1" || count != 3 || timeout != 250*time.Millisecond {
+		t.Fatalf("unexpected values: addr=%v count=%d timeout=%v", addr, count, timeout)
+	}
+}
+
+func TestTypedPositionalCustomValue(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	level := logLevel(2)
+	p.AddPositionalValue(&level, "level", 1, true, "log level")
+	if err := p.ParseArgs([]string{"info"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if level.String() != "info" {
+		t.Fatalf("expected custom value to be assigned, got %q", level.String())
+	}
+}
+
+func TestTypedPositionalConversionError(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	sub := flaggy.NewSubcommand("scale")
+	var replicas int
+	sub.AddPositionalValue(&replicas, "replicas", 1, true, "replica count")
+	p.AttachSubcommand(sub, 1)
+
+	err := p.ParseArgs([]string{"scale", "many"})
+	var conversion *flaggy.ConversionError
+	if !errors.As(err, &conversion) {
+		t.Fatalf("expected *ConversionError, got %T: %v", err, err)
+	}
+	if !conversion.Positional || conversion.Flag != "replicas" || conversion.Position != 1 || conversion.Value != "many" {
+		t.Fatalf("unexpected error contents: %+v", conversion)
+	}
+	if !strings.Contains(err.Error(), "positional value replicas at position 1") {
+		t.Fatalf("expected error to name the positional, got %v", err)
+	}
+}
+
+func TestTypedPositionalDefaultInHelp(t *testing.T) {
+	p := flaggy.NewParser("app")
+	retries := 5
+	var port int
+	p.AddPositionalValue(&retries, "retries", 1, false, "retry count")
+	p.AddPositionalValue(&port, "port", 2, true, "port number")
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	out := strings.Join(h.Lines, "\n")
+	if !strings.Contains(out, "retry count (default: 5)") {
+		t.Fatalf("expected positional default in help:\n%s", out)
+	}
+	if !strings.Contains(out, "port number (Required)") {
+		t.Fatalf("expected zero valued required positional to show (Required):\n%s", out)
+	}
+}
+
+func TestPositionalSlice(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	sub := flaggy.NewSubcommand("copy")
+	var sources []string
+	var dest string
+	sub.AddPositionalSlice(&sources, "src", 1, 1, 0, "files to copy")
+	sub.AddPositionalValue(&dest, "dst", 2, true, "destination")
+	p.AttachSubcommand(sub, 1)
+	if err := p.ParseArgs([]string{"copy", "a.txt", "b.txt", "c.txt", "out/"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if strings.Join(sources, ",") != "a.txt,b.txt,c.txt" || dest != "out/" {
+		t.Fatalf("unexpected values: sources=%v dest=%q", sources, dest)
+	}
+}
+
+func TestPositionalSliceWithFlags(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var ports []int
+	var verbose bool
+	p.Bool(&verbose, "v", "verbose", "verbose output")
+	p.AddPositionalSlice(&ports, "ports", 1, 0, 0, "ports to scan")
+	if err := p.ParseArgs([]string{"80", "-v", "443", "8080"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if len(ports) != 3 || ports[2] != 8080 || !verbose {
+		t.Fatalf("unexpected values: ports=%v verbose=%v", ports, verbose)
+	}
+}
+
+func TestPositionalSliceReplacesDefault(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	files := []string{"."}
+	p.AddPositionalSlice(&files, "files", 1, 0, 0, "files to list")
+	if err := p.ParseArgs([]string{"a", "b"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if strings.Join(files, ",") != "a,b" {
+		t.Fatalf("expected supplied values to replace the default, got %v", files)
+	}
+}
+
+func TestPositionalSliceCounts(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		min  int
+		max  int
+		msg  string
+	}{
+		{name: "too few", args: []string{"copy", "out/"}, min: 1, msg: "Positional of subcommand copy named src expects at least 1 values, but 0 were supplied"},
+		{name: "too many", args: []string{"copy", "a", "b", "c", "out/"}, min: 1, max: 2, msg: "Positional of subcommand copy named src expects between 1 and 2 values, but 3 were supplied"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := flaggy.NewParser("app")
+			p.ErrorHandling = flaggy.ContinueOnError
+			sub := flaggy.NewSubcommand("copy")
+			var sources []string
+			var dest string
+			sub.AddPositionalSlice(&sources, "src", 1, tt.min, tt.max, "files to copy")
+			sub.AddPositionalValue(&dest, "dst", 2, true, "destination")
+			p.AttachSubcommand(sub, 1)
+			err := p.ParseArgs(tt.args)
+			var count *flaggy.PositionalCountError
+			if !errors.As(err, &count) {
+				t.Fatalf("expected *PositionalCountError, got %T: %v", err, err)
+			}
+			if count.Name != "src" || count.Subcommand != "copy" {
+				t.Fatalf("unexpected error contents: %+v", count)
+			}
+			if err.Error() != tt.msg {
+				t.Fatalf("unexpected error message: %q", err.Error())
+			}
+		})
+	}
+}
+
+func TestPositionalSliceCountOnRootParser(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var files []string
+	p.AddPositionalSlice(&files, "files", 1, 1, 2, "files to list")
+	err := p.ParseArgs([]string{"a", "b", "c"})
+	var count *flaggy.PositionalCountError
+	if !errors.As(err, &count) || count.Subcommand != "" {
+		t.Fatalf("expected *PositionalCountError without a subcommand, got %T: %v", err, err)
+	}
+	if err.Error() != "Positional files expects between 1 and 2 values, but 3 were supplied" {
+		t.Fatalf("unexpected error message: %q", err.Error())
+	}
+}
+
+func TestPositionalSliceUsage(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	sub := flaggy.NewSubcommand("copy")
+	var sources []string
+	var dest string
+	sub.AddPositionalSlice(&sources, "src", 1, 1, 0, "files to copy")
+	sub.AddPositionalValue(&dest, "dst", 2, true, "destination")
+	p.AttachSubcommand(sub, 1)
+	if err := p.ParseArgs([]string{"copy", "a", "b"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	if h.UsageString != "copy [src...] [dst]" {
+		t.Fatalf("unexpected usage string: %q", h.UsageString)
+	}
+}
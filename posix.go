@@ -0,0 +1,117 @@
+package flaggy
+
+import "strings"
+
+// expandPOSIXShortFlags splits clustered single dash arguments into
+// individual short flags when the parser has POSIXShortFlags enabled.  For
+// example, -xzvf becomes -x -z -v -f.  Bool flags are set individually and
+// the first flag that takes a value ends the cluster.  That flag receives the
+// rest of the cluster as its value, like -n5, or the following argument when
+// it is the last letter.  Arguments are left as they are when their first
+// letter is not a known short flag, when they contain an '=', or when they
+// exactly match a flag's short name.
+func (p *Parser) expandPOSIXShortFlags(args []string) []string {
+	expanded := make([]string, 0, len(args))
+	var skipNext bool
+	for i, a := range args {
+		if skipNext {
+			skipNext = false
+			expanded = append(expanded, a)
+			continue
+		}
+
+		switch {
+		case a == "--":
+			return append(expanded, args[i:]...)
+		case strings.HasPrefix(a, "--"):
+			// long flags that take a value consume the next argument as is
+			if f := p.findFlagInTree(parseFlagToName(a)); f != nil && !strings.Contains(a, "=") && !f.isBool() {
+				skipNext = true
+			}
+			expanded = append(expanded, a)
+			continue
+		case !strings.HasPrefix(a, "-") || len(a) < 2 || strings.Contains(a, "="):
+			expanded = append(expanded, a)
+			continue
+		}
+
+		name := a[1:]
+		if f := p.findShortFlagInTree(name); f != nil {
+			// a multi-letter short name that matches exactly is not a cluster
+			if !f.isBool() {
+				skipNext = true
+			}
+			expanded = append(expanded, a)
+			continue
+		}
+		if p.findShortFlagInTree(name[:1]) == nil && !p.isHelpShortFlag(name[:1]) {
+			expanded = append(expanded, a)
+			continue
+		}
+
+		letters := []rune(name)
+		for j, letter := range letters {
+			short := string(letter)
+			f := p.findShortFlagInTree(short)
+			if f == nil || f.isBool() {
+				// unknown letters are passed through so they are reported
+				expanded = append(expanded, "-"+short)
+				continue
+			}
+			if rest := string(letters[j+1:]); rest != "" {
+				expanded = append(expanded, "-"+short+"="+rest)
+			} else {
+				expanded = append(expanded, "-"+short)
+				skipNext = true
+			}
+			break
+		}
+	}
+	return expanded
+}
+
+// isHelpShortFlag reports whether the name is the built-in help flag's short
+// name.
+func (p *Parser) isHelpShortFlag(name string) bool {
+	return p.ShowHelpWithHFlag && name == helpFlagShortName
+}
+
+// findShortFlagInTree finds a flag with the supplied short name on the root
+// parser or any of its subcommands.
+func (p *Parser) findShortFlagInTree(name string) *Flag {
+	var walk func(sc *Subcommand) *Flag
+	walk = func(sc *Subcommand) *Flag {
+		for _, f := range sc.Flags {
+			if f.ShortName == name {
+				return f
+			}
+		}
+		for _, child := range sc.Subcommands {
+			if f := walk(child); f != nil {
+				return f
+			}
+		}
+		return nil
+	}
+	return walk(&p.Subcommand)
+}
+
+// findFlagInTree finds a flag with the supplied short or long name on the
+// root parser or any of its subcommands.
+func (p *Parser) findFlagInTree(name string) *Flag {
+	var walk func(sc *Subcommand) *Flag
+	walk = func(sc *Subcommand) *Flag {
+		for _, f := range sc.Flags {
+			if f.HasName(name) {
+				return f
+			}
+		}
+		for _, child := range sc.Subcommands {
+			if f := walk(child); f != nil {
+				return f
+			}
+		}
+		return nil
+	}
+	return walk(&p.Subcommand)
+}
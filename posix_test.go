@@ -0,0 +1,111 @@
+package flaggy_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestPOSIXShortFlagCluster(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.POSIXShortFlags = true
+	var extract, gzip, verbose, verboseLong bool
+	var file string
+	p.Bool(&extract, "x", "extract", "extract files")
+	p.Bool(&gzip, "z", "gzip", "use gzip")
+	p.Bool(&verbose, "v", "", "verbose output")
+	p.String(&file, "f", "file", "archive file")
+	p.Bool(&verboseLong, "", "xzv", "long flag spelled like a cluster")
+	if err := p.ParseArgs([]string{"-xzvf", "archive.tgz"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if !extract || !gzip || !verbose || file != "archive.tgz" {
+		t.Fatalf("unexpected values: extract=%v gzip=%v verbose=%v file=%q", extract, gzip, verbose, file)
+	}
+	if verboseLong {
+		t.Fatal("expected cluster to be split instead of matching a long name")
+	}
+}
+
+func TestPOSIXShortFlagAttachedValue(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.POSIXShortFlags = true
+	var gzip bool
+	var file string
+	var level int
+	p.Bool(&gzip, "z", "gzip", "use gzip")
+	p.String(&file, "f", "file", "archive file")
+	p.Int(&level, "n", "level", "compression level")
+	if err := p.ParseArgs([]string{"-zn5", "-farchive.tgz"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if !gzip || level != 5 || file != "archive.tgz" {
+		t.Fatalf("unexpected values: gzip=%v level=%d file=%q", gzip, level, file)
+	}
+}
+
+func TestPOSIXShortFlagValueNotSplit(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.POSIXShortFlags = true
+	var extract, gzip bool
+	var file string
+	var level int
+	p.Bool(&extract, "x", "extract", "extract files")
+	p.Bool(&gzip, "z", "gzip", "use gzip")
+	p.String(&file, "f", "file", "archive file")
+	p.Int(&level, "n", "level", "compression level")
+	if err := p.ParseArgs([]string{"--file", "-xz", "-n", "-3"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if file != "-xz" || extract || level != -3 {
+		t.Fatalf("expected values of flags to be left alone: file=%q extract=%v level=%d", file, extract, level)
+	}
+}
+
+func TestPOSIXShortFlagUnknownLetter(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.POSIXShortFlags = true
+	var extract bool
+	p.Bool(&extract, "x", "extract", "extract files")
+	err := p.ParseArgs([]string{"-xq"})
+	if err == nil || !strings.Contains(err.Error(), "q") {
+		t.Fatalf("expected unknown letter to be reported, got %v", err)
+	}
+}
+
+func TestSingleDashLongFlagWithoutPOSIXMode(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var extract, gzip, verbose, verboseLong bool
+	var file string
+	p.Bool(&extract, "x", "extract", "extract files")
+	p.Bool(&gzip, "z", "gzip", "use gzip")
+	p.Bool(&verbose, "v", "", "verbose output")
+	p.String(&file, "f", "file", "archive file")
+	p.Bool(&verboseLong, "", "xzv", "long flag spelled like a cluster")
+	if err := p.ParseArgs([]string{"-xzv", "-file", "archive.tgz"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if !verboseLong || extract || gzip || verbose || file != "archive.tgz" {
+		t.Fatalf("expected single dash long names to be used: xzv=%v extract=%v gzip=%v verbose=%v file=%q", verboseLong, extract, gzip, verbose, file)
+	}
+}
+
+func TestSingleDashLongFlagWithPOSIXMode(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.POSIXShortFlags = true
+	var file string
+	p.String(&file, "f", "file", "archive file")
+	if err := p.ParseArgs([]string{"-file"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if file != "ile" {
+		t.Fatalf("expected -file to be read as -f with an attached value, got %q", file)
+	}
+}
@@ -0,0 +1,345 @@
+package flaggy
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"maps"
+	"math/big"
+	"net"
+	"net/netip"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// typeHandler assigns and formats one type of assignment variable.  Both
+// functions receive a pointer to the registered type.
+type typeHandler struct {
+	assign func(assignmentVar interface{}, value string) error
+	format func(assignmentVar interface{}) (string, error)
+	slice  bool // indicates each assignment appends to a slice
+}
+
+// typeRegistry maps pointer types, like *int or *[]int, to their handlers.
+var typeRegistry = make(map[reflect.Type]typeHandler)
+
+// RegisterType adds support for flags and positional values of type T and
+// []T.  The parse function converts a single argument into a T and the
+// format function renders a T for help output and completion.  Slices are
+// filled by appending one parsed argument at a time and are rendered as
+// their formatted elements joined by commas.  Registering a type that is
+// already registered replaces its handlers.  Types should be registered
+// before any parsing is done, typically from an init function.
+func RegisterType[T any](parse func(string) (T, error), format func(T) string) {
+	typeRegistry[reflect.TypeFor[*T]()] = typeHandler{
+		assign: func(assignmentVar interface{}, value string) error {
+			v, err := parse(value)
+			if err != nil {
+				return err
+			}
+			*assignmentVar.(*T) = v
+			return nil
+		},
+		format: func(assignmentVar interface{}) (string, error) {
+			return format(*assignmentVar.(*T)), nil
+		},
+	}
+	typeRegistry[reflect.TypeFor[*[]T]()] = typeHandler{
+		assign: func(assignmentVar interface{}, value string) error {
+			v, err := parse(value)
+			if err != nil {
+				return err
+			}
+			existing := assignmentVar.(*[]T)
+			*existing = append(*existing, v)
+			return nil
+		},
+		format: func(assignmentVar interface{}) (string, error) {
+			var strSlice []string
+			for _, v := range *assignmentVar.(*[]T) {
+				strSlice = append(strSlice, format(v))
+			}
+			return strings.Join(strSlice, ","), nil
+		},
+		slice: true,
+	}
+}
+
+// lookupType returns the registered handler for the assignment variable.
+func lookupType(assignmentVar interface{}) (typeHandler, bool) {
+	handler, ok := typeRegistry[reflect.TypeOf(assignmentVar)]
+	return handler, ok
+}
+
+func init() {
+	RegisterType(func(s string) (string, error) { return s, nil },
+		func(v string) string { return v })
+	RegisterType(strconv.ParseBool, strconv.FormatBool)
+	RegisterType(time.ParseDuration, time.Duration.String)
+	RegisterType(func(s string) (float32, error) {
+		v, err := strconv.ParseFloat(s, 32)
+		return float32(v), err
+	}, func(v float32) string { return strconv.FormatFloat(float64(v), 'f', 2, 32) })
+	RegisterType(func(s string) (float64, error) { return strconv.ParseFloat(s, 64) },
+		func(v float64) string { return strconv.FormatFloat(v, 'f', 2, 64) })
+	RegisterType(strconv.Atoi, strconv.Itoa)
+	registerInt[int64](64)
+	registerInt[int32](32)
+	registerInt[int16](16)
+	registerInt[int8](8)
+	registerUint[uint](64)
+	registerUint[uint64](64)
+	registerUint[uint32](32)
+	registerUint[uint16](16)
+	registerUint[uint8](8)
+
+	RegisterType(func(s string) (net.IP, error) { return net.ParseIP(s), nil },
+		net.IP.String)
+	RegisterType(net.ParseMAC, net.HardwareAddr.String)
+	RegisterType(func(s string) (net.IPMask, error) { return net.IPMask(net.ParseIP(s).To4()), nil },
+		net.IPMask.String)
+	RegisterType(func(s string) (net.IPNet, error) {
+		_, ipnet, err := net.ParseCIDR(s)
+		if err != nil {
+			return net.IPNet{}, err
+		}
+		return *ipnet, nil
+	}, func(v net.IPNet) string { return v.String() })
+	RegisterType(func(s string) (net.TCPAddr, error) {
+		ip, port, err := parseHostPort(s)
+		return net.TCPAddr{IP: ip, Port: port}, err
+	}, func(v net.TCPAddr) string { return v.String() })
+	RegisterType(func(s string) (net.UDPAddr, error) {
+		ip, port, err := parseHostPort(s)
+		return net.UDPAddr{IP: ip, Port: port}, err
+	}, func(v net.UDPAddr) string { return v.String() })
+	RegisterType(netip.ParseAddr, netip.Addr.String)
+	RegisterType(netip.ParsePrefix, netip.Prefix.String)
+	RegisterType(netip.ParseAddrPort, netip.AddrPort.String)
+	RegisterType(func(s string) (url.URL, error) {
+		u, err := url.Parse(s)
+		if err != nil {
+			return url.URL{}, err
+		}
+		return *u, nil
+	}, func(v url.URL) string { return v.String() })
+
+	RegisterType(parseTime, func(v time.Time) string {
+		if v.IsZero() {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339Nano)
+	})
+	RegisterType(parseLocation, func(v time.Location) string { return v.String() })
+	RegisterType(func(s string) (time.Month, error) {
+		if m, ok := parseMonth(s); ok {
+			return m, nil
+		}
+		return 0, fmt.Errorf("invalid time.Month: %s", s)
+	}, func(v time.Month) string {
+		if v == 0 {
+			return ""
+		}
+		return v.String()
+	})
+	RegisterType(func(s string) (time.Weekday, error) {
+		if d, ok := parseWeekday(s); ok {
+			return d, nil
+		}
+		return 0, fmt.Errorf("invalid time.Weekday: %s", s)
+	}, time.Weekday.String)
+
+	RegisterType(func(s string) (os.FileMode, error) {
+		v, err := strconv.ParseUint(s, 0, 32)
+		return os.FileMode(v), err
+	}, func(v os.FileMode) string { return fmt.Sprintf("%#o", v) })
+	RegisterType(func(s string) (regexp.Regexp, error) {
+		r, err := regexp.Compile(s)
+		if err != nil {
+			return regexp.Regexp{}, err
+		}
+		return *r, nil
+	}, func(v regexp.Regexp) string { return v.String() })
+	RegisterType(func(s string) (big.Int, error) {
+		var bi big.Int
+		if _, ok := bi.SetString(s, 0); !ok {
+			return bi, fmt.Errorf("invalid big.Int: %s", s)
+		}
+		return bi, nil
+	}, func(v big.Int) string { return v.String() })
+	RegisterType(func(s string) (big.Rat, error) {
+		var br big.Rat
+		if _, ok := br.SetString(s); !ok {
+			return br, fmt.Errorf("invalid big.Rat: %s", s)
+		}
+		return br, nil
+	}, func(v big.Rat) string { return v.RatString() })
+	registerMap(func(s string) (string, error) { return s, nil },
+		func(v string) string { return v })
+	registerMap(strconv.Atoi, strconv.Itoa)
+	RegisterType(parseBase64Bytes, func(v Base64Bytes) string {
+		if len(v) == 0 {
+			return ""
+		}
+		return base64.StdEncoding.EncodeToString([]byte(v))
+	})
+}
+
+// registerMap registers a map from strings to V.  Each argument holds one or
+// more comma separated key=value pairs, which are added to the map.  Maps are
+// rendered as their pairs sorted by key.
+func registerMap[V any](parse func(string) (V, error), format func(V) string) {
+	typeRegistry[reflect.TypeFor[*map[string]V]()] = typeHandler{
+		assign: func(assignmentVar interface{}, value string) error {
+			parsed := make(map[string]V)
+			for _, pair := range strings.Split(value, ",") {
+				k, v, ok := strings.Cut(pair, "=")
+				if !ok || k == "" {
+					return fmt.Errorf("malformed key=value pair %q", pair)
+				}
+				pv, err := parse(v)
+				if err != nil {
+					return fmt.Errorf("invalid value for key %s: %w", k, err)
+				}
+				parsed[k] = pv
+			}
+			m := assignmentVar.(*map[string]V)
+			if *m == nil {
+				*m = make(map[string]V, len(parsed))
+			}
+			for k, v := range parsed {
+				(*m)[k] = v
+			}
+			return nil
+		},
+		format: func(assignmentVar interface{}) (string, error) {
+			m := *assignmentVar.(*map[string]V)
+			pairs := make([]string, 0, len(m))
+			for _, k := range slices.Sorted(maps.Keys(m)) {
+				pairs = append(pairs, k+"="+format(m[k]))
+			}
+			return strings.Join(pairs, ","), nil
+		},
+	}
+}
+
+// registerInt registers a signed integer type of the supplied bit size.
+func registerInt[T int8 | int16 | int32 | int64](bitSize int) {
+	RegisterType(func(s string) (T, error) {
+		v, err := strconv.ParseInt(s, 10, bitSize)
+		return T(v), err
+	}, func(v T) string { return strconv.FormatInt(int64(v), 10) })
+}
+
+// registerUint registers an unsigned integer type of the supplied bit size.
+func registerUint[T uint | uint8 | uint16 | uint32 | uint64](bitSize int) {
+	RegisterType(func(s string) (T, error) {
+		v, err := strconv.ParseUint(s, 10, bitSize)
+		return T(v), err
+	}, func(v T) string { return strconv.FormatUint(uint64(v), 10) })
+}
+
+// parseHostPort splits a host:port address for TCP and UDP flags.  An empty
+// host leaves the IP unset.
+func parseHostPort(s string) (net.IP, int, error) {
+	host, portStr, err := net.SplitHostPort(s)
+	if err != nil {
+		return nil, 0, err
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil {
+		return nil, 0, err
+	}
+	var ip net.IP
+	if len(host) > 0 {
+		ip = net.ParseIP(host)
+	}
+	return ip, port, nil
+}
+
+// parseTime supports unix seconds if numeric, else tries common layouts.
+func parseTime(s string) (time.Time, error) {
+	if isAllDigits(s) {
+		sec, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return time.Time{}, err
+		}
+		return time.Unix(sec, 0).UTC(), nil
+	}
+	var parsed time.Time
+	var err error
+	layouts := []string{time.RFC3339Nano, time.RFC3339, time.RFC1123Z, time.RFC1123}
+	for _, layout := range layouts {
+		parsed, err = time.Parse(layout, s)
+		if err == nil {
+			return parsed, nil
+		}
+	}
+	return time.Time{}, err
+}
+
+// parseLocation tries an IANA name, with fallback to a UTC offset like
+// +02:00 or -0700.
+func parseLocation(s string) (time.Location, error) {
+	if loc, err := time.LoadLocation(s); err == nil {
+		return *loc, nil
+	}
+	if off, ok := parseUTCOffset(s); ok {
+		return *time.FixedZone(offsetName(off), off), nil
+	}
+	return time.Location{}, fmt.Errorf("invalid time.Location: %s", s)
+}
+
+// parseBase64Bytes tries standard then URL encoding.
+func parseBase64Bytes(s string) (Base64Bytes, error) {
+	decoded, err := base64.StdEncoding.DecodeString(s)
+	if err == nil {
+		return Base64Bytes(decoded), nil
+	}
+	if decodedURL, errURL := base64.URLEncoding.DecodeString(s); errURL == nil {
+		return Base64Bytes(decodedURL), nil
+	}
+	return nil, err
+}
+
+// FlagOf adds a flag of type T to the subcommand and returns a pointer to
+// its value, which starts out as def.  T may be any registered type or a
+// custom value.  Use &p.Subcommand to add the flag to a parser, or nil to add
+// it to the default parser.  Unsupported types panic.
+//
+//	port := flaggy.FlagOf(sc, "p", "port", "Port to listen on", 8080)
+func FlagOf[T any](sc *Subcommand, shortName string, longName string, description string, def T) *T {
+	v := new(T)
+	*v = def
+	addGeneric(sc, v, shortName, longName, description)
+	return v
+}
+
+// Slice adds a slice flag of element type T to the subcommand and returns a
+// pointer to its value, which starts out as def.  Specify the flag multiple
+// times to fill the slice.  Use &p.Subcommand to add the flag to a parser, or
+// nil to add it to the default parser.  Unsupported types panic.
+func Slice[T any](sc *Subcommand, shortName string, longName string, description string, def []T) *[]T {
+	v := new([]T)
+	*v = def
+	addGeneric(sc, v, shortName, longName, description)
+	return v
+}
+
+// addGeneric adds a flag created by FlagOf or Slice after making sure its
+// type can be parsed.
+func addGeneric(sc *Subcommand, assignmentVar interface{}, shortName string, longName string, description string) {
+	if sc == nil {
+		sc = &DefaultParser.Subcommand
+	}
+	if _, supported, _ := valueAsString(assignmentVar); !supported {
+		log.Panicln("Flag " + longName + " added to subcommand " + sc.Name + " with unsupported type " + reflect.TypeOf(assignmentVar).Elem().String())
+	}
+	sc.add(assignmentVar, shortName, longName, description)
+}
@@ -0,0 +1,122 @@
+package flaggy_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/integrii/flaggy"
+)
+
+// celsius is registered with RegisterType to exercise the type registry.
+type celsius float64
+
+func parseCelsius(s string) (celsius, error) {
+	trimmed, ok := strings.CutSuffix(s, "C")
+	if !ok {
+		return 0, errors.New("temperature must end in C: " + s)
+	}
+	v, err := strconv.ParseFloat(trimmed, 64)
+	return celsius(v), err
+}
+
+func (c celsius) String() string {
+	return strconv.FormatFloat(float64(c), 'g', -1, 64) + "C"
+}
+
+func init() {
+	flaggy.RegisterType(parseCelsius, celsius.String)
+}
+
+func TestFlagOf(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	port := flaggy.FlagOf(&p.Subcommand, "p", "port", "Port to listen on", 8080)
+	wait := flaggy.FlagOf(&p.Subcommand, "w", "wait", "Time to wait", time.Second)
+	name := flaggy.FlagOf(&p.Subcommand, "n", "name", "Name", "default")
+	if err := p.ParseArgs([]string{"-p", "9090", "--wait=5m"}); err != nil {
+		t.Fatal(err)
+	}
+	if *port != 9090 || *wait != 5*time.Minute || *name != "default" {
+		t.Fatalf("unexpected values: %d %v %q", *port, *wait, *name)
+	}
+}
+
+func TestSlice(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	hosts := flaggy.Slice(&p.Subcommand, "", "host", "Hosts", []string{"a"})
+	sizes := flaggy.Slice[uint16](&p.Subcommand, "s", "size", "Sizes", nil)
+	if err := p.ParseArgs([]string{"--host", "b", "-s", "1", "-s=2"}); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(*hosts, ",") != "a,b" {
+		t.Fatalf("unexpected hosts %v", *hosts)
+	}
+	if len(*sizes) != 2 || (*sizes)[0] != 1 || (*sizes)[1] != 2 {
+		t.Fatalf("unexpected sizes %v", *sizes)
+	}
+}
+
+func TestFlagOfConversionError(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	flaggy.FlagOf(&p.Subcommand, "", "count", "Count", int8(0))
+	err := p.ParseArgs([]string{"--count", "300"})
+	var conversion *flaggy.ConversionError
+	if !errors.As(err, &conversion) {
+		t.Fatalf("expected *ConversionError, got %T: %v", err, err)
+	}
+}
+
+func TestRegisterType(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	temp := flaggy.FlagOf(&p.Subcommand, "t", "temp", "Temperature", celsius(20))
+	temps := flaggy.Slice[celsius](&p.Subcommand, "", "temps", "Temperatures", nil)
+	var positional celsius
+	p.AddPositionalValue(&positional, "target", 1, false, "Target temperature")
+	if err := p.ParseArgs([]string{"-t", "21.5C", "--temps", "1C", "--temps", "2C", "30C"}); err != nil {
+		t.Fatal(err)
+	}
+	if *temp != 21.5 || len(*temps) != 2 || (*temps)[1] != 2 || positional != 30 {
+		t.Fatalf("unexpected values: %v %v %v", *temp, *temps, positional)
+	}
+
+	p = flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	flaggy.FlagOf(&p.Subcommand, "t", "temp", "Temperature", celsius(20))
+	if err := p.ParseArgs([]string{"-t", "hot"}); err == nil {
+		t.Fatal("expected an error for an invalid temperature")
+	}
+}
+
+func TestRegisteredTypeDefaultInHelp(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	flaggy.FlagOf(&p.Subcommand, "t", "temp", "Temperature", celsius(20))
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	for _, f := range h.Flags {
+		if strings.TrimPrefix(f.LongName, "--") == "temp" {
+			if f.DefaultValue != "20C" {
+				t.Fatalf("expected default 20C, got %q", f.DefaultValue)
+			}
+			return
+		}
+	}
+	t.Fatal("expected the temp flag in help")
+}
+
+func TestFlagOfUnsupportedTypePanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected a panic for an unsupported type")
+		}
+	}()
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	flaggy.FlagOf(&p.Subcommand, "", "chan", "A channel", make(chan int))
+}
@@ -0,0 +1,95 @@
+package flaggy_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestRequiredFlagsReportedTogether(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var cluster, image, target string
+	p.String(&cluster, "c", "cluster", "cluster name").Required = true
+	deploy := flaggy.NewSubcommand("deploy")
+	deploy.String(&image, "i", "image", "image to deploy").Required = true
+	p.AttachSubcommand(deploy, 1)
+	// required flags of subcommands that are not used are not reported
+	destroy := flaggy.NewSubcommand("destroy")
+	destroy.String(&target, "t", "target", "target to destroy").Required = true
+	p.AttachSubcommand(destroy, 1)
+	err := p.ParseArgs([]string{"deploy"})
+	var required *flaggy.RequiredFlagError
+	if !errors.As(err, &required) {
+		t.Fatalf("expected *RequiredFlagError, got %T: %v", err, err)
+	}
+	if strings.Join(required.Flags, ",") != "--cluster,--image" {
+		t.Fatalf("expected cluster and image to be reported, got %v", required.Flags)
+	}
+}
+
+func TestRequiredFlagsSatisfied(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var cluster, image string
+	p.String(&cluster, "c", "cluster", "cluster name").Required = true
+	deploy := flaggy.NewSubcommand("deploy")
+	deploy.String(&image, "i", "image", "image to deploy").Required = true
+	p.AttachSubcommand(deploy, 1)
+	if err := p.ParseArgs([]string{"-c", "prod", "deploy", "--image=web:1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRequiredFlagSatisfiedByEnvironment(t *testing.T) {
+	t.Setenv("TEST_REQUIRED_CLUSTER", "prod")
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var cluster string
+	f := p.String(&cluster, "c", "cluster", "cluster name")
+	f.Required = true
+	f.EnvVar = "TEST_REQUIRED_CLUSTER"
+	if err := p.ParseArgs([]string{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRequiredFlagExitsWithHelp(t *testing.T) {
+	p := flaggy.NewParser("app")
+	var cluster string
+	p.String(&cluster, "c", "cluster", "cluster name").Required = true
+	defer func() {
+		r := recover()
+		if r == nil || !strings.Contains(r.(string), "Panic instead of exit with code: 2") {
+			t.Fatalf("expected exit with code 2, got %v", r)
+		}
+	}()
+	p.ParseArgs([]string{})
+	t.Fatal("expected parse to exit")
+}
+
+func TestRequiredFlagShownInHelp(t *testing.T) {
+	p := flaggy.NewParser("app")
+	var cluster, region string
+	p.String(&cluster, "c", "cluster", "cluster name").Required = true
+	p.String(&region, "r", "region", "region name")
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	var found bool
+	for _, line := range h.Lines {
+		if strings.Contains(line, "--cluster") {
+			found = true
+			if !strings.HasSuffix(line, "cluster name (Required)") {
+				t.Fatalf("expected required marker on cluster flag, got %q", line)
+			}
+		}
+		if strings.Contains(line, "--region") && strings.Contains(line, "(Required)") {
+			t.Fatalf("expected region flag to be optional, got %q", line)
+		}
+	}
+	if !found {
+		t.Fatal("cluster flag missing from help output")
+	}
+}
@@ -0,0 +1,132 @@
+package flaggy
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// RunFunc is the signature of subcommand handlers and their hooks.
+type RunFunc func(ctx context.Context, args RunArgs) error
+
+// RunArgs is passed to subcommand handlers and hooks when a parser is
+// executed.
+type RunArgs struct {
+	Parser            *Parser       // the parser that was executed
+	Subcommand        *Subcommand   // the most specific subcommand that was used
+	Path              []*Subcommand // the subcommands used, starting with the root parser
+	TrailingArguments []string      // everything after a --
+}
+
+// ExitError wraps an error returned by a handler with the status code the
+// program should exit with.
+type ExitError struct {
+	Code int
+	Err  error
+}
+
+func (e *ExitError) Error() string {
+	if e.Err == nil {
+		return "exit status " + strconv.Itoa(e.Code)
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *ExitError) Unwrap() error {
+	return e.Err
+}
+
+// ExitCode maps an error returned by Execute to a process exit code.  Nil,
+// ErrHelp, ErrVersion and ErrCompletion map to 0, invalid arguments and values that can not
+// be converted or fail validation map to 2, an *ExitError maps to its Code, and any other error
+// maps to 1.
+func ExitCode(err error) int {
+	if err == nil || errors.Is(err, ErrHelp) || errors.Is(err, ErrVersion) || errors.Is(err, ErrCompletion) {
+		return 0
+	}
+	var exitErr *ExitError
+	if errors.As(err, &exitErr) {
+		return exitErr.Code
+	}
+	var conversion *ConversionError
+	var validation *ValidationError
+	if isUsageError(err) || errors.As(err, &conversion) || errors.As(err, &validation) {
+		return 2
+	}
+	return 1
+}
+
+// Execute parses the arguments to the running binary and runs the handler of
+// the most specific subcommand used.  See ExecuteArgs.
+func (p *Parser) Execute(ctx context.Context) error {
+	return p.ExecuteArgs(ctx, os.Args[1:])
+}
+
+// ExecuteArgs parses the supplied arguments and runs the Run handler of the
+// most specific subcommand used.  The PreRun hooks of the root parser and
+// every used subcommand run first, from the root down, and the PostRun hooks
+// run afterward from the most specific subcommand back up to the root.  When
+// the subcommand has no Run handler, help is shown as if it were requested.
+// The first error from parsing, a hook, or the handler is returned.
+func (p *Parser) ExecuteArgs(ctx context.Context, args []string) error {
+	if err := p.ParseArgs(args); err != nil {
+		return err
+	}
+
+	target := p.TrailingSubcommand()
+	if target == nil || target == p.initialSubcommandContext {
+		target = &p.Subcommand
+	}
+	if target.Run == nil {
+		return p.handleError(ErrHelp)
+	}
+
+	path := append([]*Subcommand{&p.Subcommand}, p.usedSubcommandPath()...)
+	runArgs := RunArgs{
+		Parser:            p,
+		Subcommand:        target,
+		Path:              path,
+		TrailingArguments: p.TrailingArguments,
+	}
+
+	for _, sc := range path {
+		if sc.PreRun == nil {
+			continue
+		}
+		if err := sc.PreRun(ctx, runArgs); err != nil {
+			return err
+		}
+	}
+	if err := target.Run(ctx, runArgs); err != nil {
+		return err
+	}
+	for i := len(path) - 1; i >= 0; i-- {
+		if path[i].PostRun == nil {
+			continue
+		}
+		if err := path[i].PostRun(ctx, runArgs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Execute parses the arguments to the running binary with the default parser
+// and runs the handler of the most specific subcommand used.  When an error
+// is returned it is printed and the program exits with the code from
+// ExitCode.
+func Execute(ctx context.Context) {
+	err := DefaultParser.Execute(ctx)
+	TrailingArguments = DefaultParser.TrailingArguments
+	if err == nil {
+		return
+	}
+	code := ExitCode(err)
+	if code != 0 {
+		fmt.Fprintln(os.Stderr, err)
+	}
+	DefaultParser.exit(code)
+}
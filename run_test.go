@@ -0,0 +1,127 @@
+package flaggy_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestExecuteRunsHooksAndHandler(t *testing.T) {
+	var calls []string
+	record := func(name string) flaggy.RunFunc {
+		return func(ctx context.Context, args flaggy.RunArgs) error {
+			calls = append(calls, name+":"+args.Subcommand.Name)
+			return nil
+		}
+	}
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.PreRun = record("root-pre")
+	p.PostRun = record("root-post")
+	p.Run = record("root-run")
+	deploy := flaggy.NewSubcommand("deploy")
+	deploy.PreRun = record("deploy-pre")
+	deploy.PostRun = record("deploy-post")
+	deploy.Run = record("deploy-run")
+	canary := flaggy.NewSubcommand("canary")
+	canary.Run = record("canary-run")
+	deploy.AttachSubcommand(canary, 1)
+	p.AttachSubcommand(deploy, 1)
+
+	if err := p.ExecuteArgs(context.Background(), []string{"deploy", "canary"}); err != nil {
+		t.Fatalf("execute error: %v", err)
+	}
+	want := "root-pre:canary,deploy-pre:canary,canary-run:canary,deploy-post:canary,root-post:canary"
+	if got := strings.Join(calls, ","); got != want {
+		t.Fatalf("unexpected call order:\n got: %s\nwant: %s", got, want)
+	}
+}
+
+func TestExecuteRunsRootHandler(t *testing.T) {
+	var calls []string
+	record := func(name string) flaggy.RunFunc {
+		return func(ctx context.Context, args flaggy.RunArgs) error {
+			calls = append(calls, name+":"+args.Subcommand.Name)
+			return nil
+		}
+	}
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.PreRun = record("root-pre")
+	p.PostRun = record("root-post")
+	p.Run = record("root-run")
+	deploy := flaggy.NewSubcommand("deploy")
+	deploy.Run = record("deploy-run")
+	p.AttachSubcommand(deploy, 1)
+
+	if err := p.ExecuteArgs(context.Background(), []string{}); err != nil {
+		t.Fatalf("execute error: %v", err)
+	}
+	if got := strings.Join(calls, ","); got != "root-pre:app,root-run:app,root-post:app" {
+		t.Fatalf("unexpected calls: %s", got)
+	}
+}
+
+func TestExecuteHandlerError(t *testing.T) {
+	var calls []string
+	record := func(name string) flaggy.RunFunc {
+		return func(ctx context.Context, args flaggy.RunArgs) error {
+			calls = append(calls, name+":"+args.Subcommand.Name)
+			return nil
+		}
+	}
+	failure := errors.New("deploy failed")
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.PostRun = record("root-post")
+	deploy := flaggy.NewSubcommand("deploy")
+	deploy.PostRun = record("deploy-post")
+	deploy.Run = func(ctx context.Context, args flaggy.RunArgs) error {
+		return &flaggy.ExitError{Code: 3, Err: failure}
+	}
+	p.AttachSubcommand(deploy, 1)
+
+	err := p.ExecuteArgs(context.Background(), []string{"deploy"})
+	if !errors.Is(err, failure) {
+		t.Fatalf("expected handler error, got %v", err)
+	}
+	if code := flaggy.ExitCode(err); code != 3 {
+		t.Fatalf("expected exit code 3, got %d", code)
+	}
+	if len(calls) > 0 {
+		t.Fatalf("expected post hooks to be skipped after a failure: %v", calls)
+	}
+}
+
+func TestExecuteWithoutHandlerShowsHelp(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	p.AttachSubcommand(flaggy.NewSubcommand("status"), 1)
+	err := p.ExecuteArgs(context.Background(), []string{"status"})
+	if !errors.Is(err, flaggy.ErrHelp) {
+		t.Fatalf("expected ErrHelp, got %v", err)
+	}
+}
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, 0},
+		{flaggy.ErrHelp, 0},
+		{flaggy.ErrVersion, 0},
+		{&flaggy.UnknownArgumentError{Arguments: []string{"x"}}, 2},
+		{&flaggy.ConversionError{Flag: "n", Value: "x", Err: errors.New("bad")}, 2},
+		{&flaggy.ExitError{Code: 7}, 7},
+		{errors.New("boom"), 1},
+	}
+	for _, tt := range tests {
+		if got := flaggy.ExitCode(tt.err); got != tt.want {
+			t.Errorf("ExitCode(%v) = %d, want %d", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package flaggy
+
+// ValueSource describes where the value of a flag came from.
+type ValueSource int
+
+const (
+	// SourceDefault means the flag still holds the value it was defined with.
+	SourceDefault ValueSource = iota
+	// SourceCommandLine means the value was supplied as an argument.
+	SourceCommandLine
+	// SourceEnvironment means the value was read from an environment variable.
+	SourceEnvironment
+	// SourceConfigFile means the value was read from a config file.
+	SourceConfigFile
+	// SourceProgrammatic means the value was set with SetValueForKey.
+	SourceProgrammatic
+)
+
+// String returns a short name for the source, like "cli" or "env".
+func (s ValueSource) String() string {
+	switch s {
+	case SourceDefault:
+		return "default"
+	case SourceCommandLine:
+		return "cli"
+	case SourceEnvironment:
+		return "env"
+	case SourceConfigFile:
+		return "config-file"
+	case SourceProgrammatic:
+		return "programmatic"
+	}
+	return "unknown"
+}
+
+// Changed reports whether the flag has been given a value by any source
+// other than its default.
+func (f *Flag) Changed() bool {
+	return f.Source != SourceDefault
+}
+
+// Changed reports whether the flag with the supplied short or long name on
+// this subcommand has been given a value.  Unknown names report false.
+func (sc *Subcommand) Changed(name string) bool {
+	for _, f := range sc.Flags {
+		if f.HasName(name) {
+			return f.Changed()
+		}
+	}
+	return false
+}
+
+// VisitAll calls fn for every flag of the parser and its subcommands, in the
+// order they were defined, starting with the root parser's flags.
+func (p *Parser) VisitAll(fn func(*Flag)) {
+	var walk func(sc *Subcommand)
+	walk = func(sc *Subcommand) {
+		for _, f := range sc.Flags {
+			fn(f)
+		}
+		for _, child := range sc.Subcommands {
+			walk(child)
+		}
+	}
+	walk(&p.Subcommand)
+}
+
+// VisitSet calls fn for every flag of the parser and its subcommands that
+// has been given a value, like the standard library's flag.Visit.  Flags are
+// visited in the same order as VisitAll.
+func (p *Parser) VisitSet(fn func(*Flag)) {
+	p.VisitAll(func(f *Flag) {
+		if f.Changed() {
+			fn(f)
+		}
+	})
+}
+
+// VisitAll calls fn for every flag of the default parser and its
+// subcommands.
+func VisitAll(fn func(*Flag)) {
+	DefaultParser.VisitAll(fn)
+}
+
+// VisitSet calls fn for every flag of the default parser and its
+// subcommands that has been given a value.
+func VisitSet(fn func(*Flag)) {
+	DefaultParser.VisitSet(fn)
+}
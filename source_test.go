@@ -0,0 +1,98 @@
+package flaggy_test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestFlagSource(t *testing.T) {
+	t.Setenv("APP_REGION", "us-east-1")
+	config := filepath.Join(t.TempDir(), "app.json")
+	if err := os.WriteFile(config, []byte(`{"profile": "prod"}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var name, region, profile, unused string
+	p.String(&name, "n", "name", "Name")
+	p.String(&region, "r", "region", "Region").EnvVar = "APP_REGION"
+	p.String(&profile, "", "profile", "Profile")
+	p.String(&unused, "u", "unused", "Unused")
+	if err := p.LoadConfigFile(config); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.ParseArgs([]string{"-n", "web"}); err != nil {
+		t.Fatal(err)
+	}
+
+	sources := make(map[string]flaggy.ValueSource)
+	p.VisitAll(func(f *flaggy.Flag) {
+		sources[f.LongName] = f.Source
+	})
+	want := map[string]flaggy.ValueSource{
+		"name":    flaggy.SourceCommandLine,
+		"region":  flaggy.SourceEnvironment,
+		"profile": flaggy.SourceConfigFile,
+		"unused":  flaggy.SourceDefault,
+	}
+	if !reflect.DeepEqual(sources, want) {
+		t.Fatalf("expected sources %v, got %v", want, sources)
+	}
+	if !p.Changed("name") || !p.Changed("r") || p.Changed("unused") || p.Changed("bogus") {
+		t.Fatal("unexpected Changed results")
+	}
+}
+
+func TestFlagSourceProgrammatic(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var name string
+	p.String(&name, "n", "name", "Name")
+	if _, err := p.SetValueForKey("name", "set"); err != nil {
+		t.Fatal(err)
+	}
+	if p.Flags[0].Source != flaggy.SourceProgrammatic || p.Flags[0].Source.String() != "programmatic" {
+		t.Fatalf("expected a programmatic source, got %v", p.Flags[0].Source)
+	}
+}
+
+func TestVisitSet(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var a, b, c string
+	p.String(&a, "a", "alpha", "Alpha")
+	p.String(&b, "b", "beta", "Beta")
+	sub := flaggy.NewSubcommand("sub")
+	sub.String(&c, "c", "gamma", "Gamma")
+	p.AttachSubcommand(sub, 1)
+	if err := p.ParseArgs([]string{"sub", "-c", "3", "-a", "1"}); err != nil {
+		t.Fatal(err)
+	}
+	var visited []string
+	p.VisitSet(func(f *flaggy.Flag) {
+		visited = append(visited, f.LongName)
+	})
+	if !reflect.DeepEqual(visited, []string{"alpha", "gamma"}) {
+		t.Fatalf("unexpected flags visited: %v", visited)
+	}
+	if !sub.Changed("gamma") {
+		t.Fatal("expected gamma to be changed on the subcommand")
+	}
+}
+
+func TestHelpDoesNotMarkFlagsChanged(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var name string
+	p.String(&name, "n", "name", "Name")
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	if p.Changed("name") {
+		t.Fatal("expected rendering help to leave flags unchanged")
+	}
+}
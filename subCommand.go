@@ -1,6 +1,8 @@
 package flaggy
 
 import (
+	"encoding"
+	"flag"
 	"fmt"
 	"log"
 	"math/big"
@@ -8,9 +10,9 @@ import (
 	netip "net/netip"
 	"net/url"
 	"os"
+	"reflect"
 	"regexp"
 	"strconv"
-	"strings"
 	"time"
 )
 
@@ -32,6 +34,12 @@ type Subcommand struct {
 	AdditionalHelpAppend  string        // additional appended message when Help is displayed
 	Used                  bool          // indicates this subcommand was found and parsed
 	Hidden                bool          // indicates this subcommand should be hidden from help
+	Aliases               []string      // alternate names that select this subcommand, in addition to ShortName
+	Deprecated            string        // when set, using this subcommand prints this message as a warning and it is hidden from help and completion
+	Run                   RunFunc       // handles this subcommand when it is the most specific one used by Parser.Execute
+	PreRun                RunFunc       // runs before the handler whenever this subcommand is used by Parser.Execute
+	PostRun               RunFunc       // runs after the handler whenever this subcommand is used by Parser.Execute
+	flagGroups            []flagGroup   // constraints between flags, checked after parsing
 }
 
 // NewSubcommand creates a new subcommand that can have flags or PositionalFlags
@@ -39,7 +47,7 @@ type Subcommand struct {
 func NewSubcommand(name string) *Subcommand {
 	if len(name) == 0 {
 		fmt.Fprintln(os.Stderr, "Error creating subcommand (NewSubcommand()).  No subcommand name was specified.")
-		exitOrPanic(2)
+		osExit(2)
 	}
 	newSC := &Subcommand{
 		Name: name,
@@ -73,7 +81,7 @@ func (sc *Subcommand) parseAllFlagsFromArgs(p *Parser, args []string) (flagScanR
 		flagName := parseFlagToName(a)
 
 		if p.ShowVersionWithVersionFlag && flagName == versionFlagLongName {
-			p.ShowVersionAndExit()
+			return result, ErrVersion
 		}
 
 		if p.ShowHelpWithHFlag && (flagName == helpFlagShortName || flagName == helpFlagLongName) {
@@ -91,26 +99,7 @@ func (sc *Subcommand) parseAllFlagsFromArgs(p *Parser, args []string) (flagScanR
 			sc.addParsedPositionalValue(a)
 
 			// Detect subcommands early so we avoid parsing child flags at this level.
-			var matched *Subcommand
-			for _, cmd := range sc.Subcommands {
-				if a == cmd.Name || a == cmd.ShortName {
-					// Prefer an exact positional match when available.
-					if cmd.Position == positionalCount {
-						matched = cmd
-						break
-					}
-					if matched == nil {
-						matched = cmd
-					}
-				}
-			}
-			if matched != nil {
-				// Ignore the tentative match when a positional value is already defined at this depth.
-				if matched.Position != positionalCount && hasPositionalAtDepth(sc, positionalCount) {
-					matched = nil
-				}
-			}
-			if matched != nil {
+			if matched := sc.matchSubcommand(a, positionalCount); matched != nil {
 				// Drop the provisional positional bookkeeping because the token actually belongs to the child.
 				if len(result.Positionals) > 0 {
 					result.Positionals = result.Positionals[:len(result.Positionals)-1]
@@ -134,9 +123,9 @@ func (sc *Subcommand) parseAllFlagsFromArgs(p *Parser, args []string) (flagScanR
 			key := flagName
 
 			if flagIsBool(sc, p, key) {
-				valueSet, err := setValueForParsers(key, "true", p, sc)
+				valueSet, err := setValueForParsers(key, "true", SourceCommandLine, &p.Subcommand, sc)
 				if err != nil {
-					return result, err
+					return result, valueError(key, "true", 0, err)
 				}
 				if valueSet {
 					sc.addParsedFlag(key, "", false)
@@ -154,14 +143,13 @@ func (sc *Subcommand) parseAllFlagsFromArgs(p *Parser, args []string) (flagScanR
 			}
 
 			if i+1 >= len(args) {
-				p.ShowHelpWithMessage("Expected a following arg for flag " + key + ", but it did not exist.")
-				exitOrPanic(2)
+				return result, &MissingValueError{Flag: key}
 			}
 
 			nextArg := args[i+1]
-			valueSet, err := setValueForParsers(key, nextArg, p, sc)
+			valueSet, err := setValueForParsers(key, nextArg, SourceCommandLine, &p.Subcommand, sc)
 			if err != nil {
-				return result, err
+				return result, valueError(key, nextArg, 0, err)
 			}
 			if valueSet {
 				sc.addParsedFlag(key, nextArg, true)
@@ -176,9 +164,9 @@ func (sc *Subcommand) parseAllFlagsFromArgs(p *Parser, args []string) (flagScanR
 				continue
 			}
 
-			valueSet, err := setValueForParsers(key, val, p, sc)
+			valueSet, err := setValueForParsers(key, val, SourceCommandLine, &p.Subcommand, sc)
 			if err != nil {
-				return result, err
+				return result, valueError(key, val, 0, err)
 			}
 			if valueSet {
 				sc.addParsedFlag(keyWithValue, val, false)
@@ -213,13 +201,13 @@ func shouldReserveNextArgForChild(sc *Subcommand, positionalCount int, nextArg s
 
 	position := positionalCount + 1
 	for _, cmd := range sc.Subcommands {
-		if cmd.Position == position && (cmd.Name == nextArg || cmd.ShortName == nextArg) {
+		if cmd.Position == position && cmd.HasName(nextArg) {
 			return false
 		}
 	}
 
 	for _, pos := range sc.PositionalFlags {
-		if pos.Position == position {
+		if pos.coversDepth(position) {
 			return false
 		}
 	}
@@ -227,9 +215,31 @@ func shouldReserveNextArgForChild(sc *Subcommand, positionalCount int, nextArg s
 	return true
 }
 
+// matchSubcommand finds the child subcommand selected by the positional
+// argument at the supplied depth.  A subcommand at exactly that position is
+// preferred, and one at another position only matches when no positional
+// value is defined at the depth.
+func (sc *Subcommand) matchSubcommand(arg string, depth int) *Subcommand {
+	var matched *Subcommand
+	for _, cmd := range sc.Subcommands {
+		if cmd.HasName(arg) {
+			if cmd.Position == depth {
+				return cmd
+			}
+			if matched == nil {
+				matched = cmd
+			}
+		}
+	}
+	if matched != nil && hasPositionalAtDepth(sc, depth) {
+		return nil
+	}
+	return matched
+}
+
 func hasPositionalAtDepth(sc *Subcommand, depth int) bool {
 	for _, pos := range sc.PositionalFlags {
-		if pos.Position == depth {
+		if pos.coversDepth(depth) {
 			return true
 		}
 	}
@@ -253,6 +263,9 @@ func (sc *Subcommand) parse(p *Parser, args []string) error {
 	if len(sc.ShortName) > 0 {
 		sc.addParsedPositionalValue(sc.ShortName)
 	}
+	for _, alias := range sc.Aliases {
+		sc.addParsedPositionalValue(alias)
+	}
 
 	// as subcommands are used, they become the context of the parser.  This helps
 	// us understand how to display help based on which subcommand is being used
@@ -261,10 +274,22 @@ func (sc *Subcommand) parse(p *Parser, args []string) error {
 	// ensure that help and version flags are not used if the parser has the
 	// built-in help and version flags enabled
 	if p.ShowHelpWithHFlag {
-		sc.ensureNoConflictWithBuiltinHelp()
+		if err := sc.ensureNoConflictWithBuiltinHelp(); err != nil {
+			return err
+		}
 	}
 	if p.ShowVersionWithVersionFlag {
-		sc.ensureNoConflictWithBuiltinVersion()
+		if err := sc.ensureNoConflictWithBuiltinVersion(); err != nil {
+			return err
+		}
+	}
+	// the aliases of the last flag added have not been checked yet
+	if alias := conflictingFlagAlias(sc.Flags); alias != "" {
+		conflict := &AliasConflictError{Subcommand: sc.Name, Alias: alias}
+		if sc == &p.Subcommand {
+			conflict.Subcommand = ""
+		}
+		return conflict
 	}
 
 	scan, err := sc.parseAllFlagsFromArgs(p, args)
@@ -284,16 +309,13 @@ func (sc *Subcommand) parse(p *Parser, args []string) error {
 		}
 
 		var foundPositional bool
-		for _, val := range sc.PositionalFlags {
-			if relativeDepth == val.Position {
-				debugPrint("Found a positional value at relativePos:", relativeDepth, "value:", value)
-
-				val.defaultValue = *val.AssignmentVar
-				*val.AssignmentVar = value
-				foundPositional = true
-				val.Found = true
-				break
+		if val := sc.positionalForDepth(relativeDepth, len(scan.Positionals)); val != nil {
+			debugPrint("Found a positional value at relativePos:", relativeDepth, "value:", value)
+
+			if err := val.assign(value, relativeDepth); err != nil {
+				return err
 			}
+			foundPositional = true
 		}
 
 		if !foundPositional {
@@ -306,24 +328,23 @@ func (sc *Subcommand) parse(p *Parser, args []string) error {
 					}
 				}
 
+				unexpected := &UnknownArgumentError{
+					Subcommand: sc.Name,
+					Arguments:  []string{value},
+					Position:   relativeDepth,
+				}
 				if foundSubcommandAtDepth {
-					fmt.Fprintln(os.Stderr, sc.Name+":", "No subcommand or positional value found at position", strconv.Itoa(relativeDepth)+".")
-					var output string
 					for _, cmd := range sc.Subcommands {
-						if cmd.Hidden {
+						if cmd.hidden() {
 							continue
 						}
-						output = output + " " + cmd.Name
+						unexpected.Subcommands = append(unexpected.Subcommands, cmd.Name)
 					}
-					if len(output) > 0 {
-						output = strings.TrimLeft(output, " ")
-						fmt.Println("Available subcommands:", output)
+					if suggestions := p.suggestSubcommands(sc, value, relativeDepth); len(suggestions) > 0 {
+						unexpected.Suggestions = map[string][]string{value: suggestions}
 					}
-					exitOrPanic(2)
 				}
-
-				p.ShowHelpWithMessage("Unexpected argument: " + value)
-				exitOrPanic(2)
+				return unexpected
 			} else {
 				p.TrailingArguments = append(p.TrailingArguments, value)
 			}
@@ -340,22 +361,26 @@ func (sc *Subcommand) parse(p *Parser, args []string) error {
 	}
 
 	if scan.HelpRequested && p.ShowHelpWithHFlag {
-		p.ShowHelp()
-		exitOrPanic(0)
+		return ErrHelp
 	}
 
 	// find any positionals that were not used on subcommands that were
 	// found and throw help (unknown argument) in the global parse or subcommand
 	for _, pv := range p.PositionalFlags {
 		if pv.Required && !pv.Found {
-			p.ShowHelpWithMessage("Required global positional variable " + pv.Name + " not found at position " + strconv.Itoa(pv.Position))
-			exitOrPanic(2)
+			return &RequiredPositionalError{Name: pv.Name, Position: pv.Position}
 		}
 	}
+	owner := sc.Name
+	if sc == &p.Subcommand {
+		owner = ""
+	}
 	for _, pv := range sc.PositionalFlags {
+		if err := pv.checkCount(owner); err != nil {
+			return err
+		}
 		if pv.Required && !pv.Found {
-			p.ShowHelpWithMessage("Required positional of subcommand " + sc.Name + " named " + pv.Name + " not found at position " + strconv.Itoa(pv.Position))
-			exitOrPanic(2)
+			return &RequiredPositionalError{Subcommand: sc.Name, Name: pv.Name, Position: pv.Position}
 		}
 	}
 
@@ -409,12 +434,15 @@ func (sc *Subcommand) AttachSubcommand(newSC *Subcommand, relativePosition int)
 					log.Panicln("Unable to add subcommand because one already exists at position" + strconv.Itoa(newSC.Position) + " with name " + other.ShortName)
 				}
 			}
+			if alias := conflictingSubcommandAlias(newSC, other); alias != "" {
+				log.Panicln("Unable to add subcommand because one already exists at position " + strconv.Itoa(newSC.Position) + " with alias " + alias)
+			}
 		}
 	}
 
 	// ensure no positionals at this depth
 	for _, other := range sc.PositionalFlags {
-		if newSC.Position == other.Position {
+		if other.coversDepth(newSC.Position) {
 			log.Panicln("Unable to add subcommand because a positional value already exists at position " + strconv.Itoa(newSC.Position) + ": " + other.Name)
 		}
 	}
@@ -424,8 +452,9 @@ func (sc *Subcommand) AttachSubcommand(newSC *Subcommand, relativePosition int)
 
 // add is a "generic" to add flags of any type. Checks the supplied parent
 // parser to ensure that the user isn't setting version or help flags that
-// conflict with the built-in help and version flag behavior.
-func (sc *Subcommand) add(assignmentVar interface{}, shortName string, longName string, description string) {
+// conflict with the built-in help and version flag behavior.  The created
+// flag is returned so callers can set optional fields such as EnvVar.
+func (sc *Subcommand) add(assignmentVar interface{}, shortName string, longName string, description string) *Flag {
 
 	// if the flag is already used, throw an error
 	for _, existingFlag := range sc.Flags {
@@ -443,300 +472,370 @@ func (sc *Subcommand) add(assignmentVar interface{}, shortName string, longName
 		LongName:      longName,
 		Description:   description,
 	}
+	// aliases are set after a flag is added, so those of the flags added
+	// before this one are checked now
+	if alias := conflictingFlagAlias(append(sc.Flags[:len(sc.Flags):len(sc.Flags)], &newFlag)); alias != "" {
+		log.Panicln("Flag alias " + alias + " on subcommand " + sc.Name + " is already assigned to another flag.")
+	}
 	sc.Flags = append(sc.Flags, &newFlag)
+	return &newFlag
 }
 
 // String adds a new string flag
-func (sc *Subcommand) String(assignmentVar *string, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) String(assignmentVar *string, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // StringSlice adds a new slice of strings flag
 // Specify the flag multiple times to fill the slice
-func (sc *Subcommand) StringSlice(assignmentVar *[]string, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) StringSlice(assignmentVar *[]string, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Bool adds a new bool flag
-func (sc *Subcommand) Bool(assignmentVar *bool, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Bool(assignmentVar *bool, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // BoolSlice adds a new slice of bools flag
 // Specify the flag multiple times to fill the slice
-func (sc *Subcommand) BoolSlice(assignmentVar *[]bool, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) BoolSlice(assignmentVar *[]bool, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // ByteSlice adds a new slice of bytes flag
 // Specify the flag multiple times to fill the slice.  Takes hex as input.
-func (sc *Subcommand) ByteSlice(assignmentVar *[]byte, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) ByteSlice(assignmentVar *[]byte, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // BytesBase64 adds a new []byte flag parsed from base64 input.
-func (sc *Subcommand) BytesBase64(assignmentVar *Base64Bytes, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) BytesBase64(assignmentVar *Base64Bytes, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Duration adds a new time.Duration flag.
 // Input format is described in time.ParseDuration().
 // Example values: 1h, 1h50m, 32s
-func (sc *Subcommand) Duration(assignmentVar *time.Duration, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Duration(assignmentVar *time.Duration, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // DurationSlice adds a new time.Duration flag.
 // Input format is described in time.ParseDuration().
 // Example values: 1h, 1h50m, 32s
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) DurationSlice(assignmentVar *[]time.Duration, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) DurationSlice(assignmentVar *[]time.Duration, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Float32 adds a new float32 flag.
-func (sc *Subcommand) Float32(assignmentVar *float32, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Float32(assignmentVar *float32, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Float32Slice adds a new float32 flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) Float32Slice(assignmentVar *[]float32, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Float32Slice(assignmentVar *[]float32, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Float64 adds a new float64 flag.
-func (sc *Subcommand) Float64(assignmentVar *float64, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Float64(assignmentVar *float64, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Float64Slice adds a new float64 flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) Float64Slice(assignmentVar *[]float64, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Float64Slice(assignmentVar *[]float64, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Int adds a new int flag
-func (sc *Subcommand) Int(assignmentVar *int, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Int(assignmentVar *int, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // IntSlice adds a new int slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) IntSlice(assignmentVar *[]int, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) IntSlice(assignmentVar *[]int, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt adds a new uint flag
-func (sc *Subcommand) UInt(assignmentVar *uint, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UInt(assignmentVar *uint, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UIntSlice adds a new uint slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) UIntSlice(assignmentVar *[]uint, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UIntSlice(assignmentVar *[]uint, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt64 adds a new uint64 flag
-func (sc *Subcommand) UInt64(assignmentVar *uint64, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UInt64(assignmentVar *uint64, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt64Slice adds a new uint64 slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) UInt64Slice(assignmentVar *[]uint64, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UInt64Slice(assignmentVar *[]uint64, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt32 adds a new uint32 flag
-func (sc *Subcommand) UInt32(assignmentVar *uint32, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UInt32(assignmentVar *uint32, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt32Slice adds a new uint32 slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) UInt32Slice(assignmentVar *[]uint32, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UInt32Slice(assignmentVar *[]uint32, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt16 adds a new uint16 flag
-func (sc *Subcommand) UInt16(assignmentVar *uint16, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UInt16(assignmentVar *uint16, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt16Slice adds a new uint16 slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) UInt16Slice(assignmentVar *[]uint16, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UInt16Slice(assignmentVar *[]uint16, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt8 adds a new uint8 flag
-func (sc *Subcommand) UInt8(assignmentVar *uint8, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UInt8(assignmentVar *uint8, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UInt8Slice adds a new uint8 slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) UInt8Slice(assignmentVar *[]uint8, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UInt8Slice(assignmentVar *[]uint8, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Int64 adds a new int64 flag.
-func (sc *Subcommand) Int64(assignmentVar *int64, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Int64(assignmentVar *int64, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Int64Slice adds a new int64 slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) Int64Slice(assignmentVar *[]int64, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Int64Slice(assignmentVar *[]int64, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Int32 adds a new int32 flag
-func (sc *Subcommand) Int32(assignmentVar *int32, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Int32(assignmentVar *int32, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Int32Slice adds a new int32 slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) Int32Slice(assignmentVar *[]int32, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Int32Slice(assignmentVar *[]int32, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Int16 adds a new int16 flag
-func (sc *Subcommand) Int16(assignmentVar *int16, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Int16(assignmentVar *int16, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Int16Slice adds a new int16 slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) Int16Slice(assignmentVar *[]int16, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Int16Slice(assignmentVar *[]int16, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Int8 adds a new int8 flag
-func (sc *Subcommand) Int8(assignmentVar *int8, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Int8(assignmentVar *int8, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Int8Slice adds a new int8 slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) Int8Slice(assignmentVar *[]int8, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Int8Slice(assignmentVar *[]int8, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // IP adds a new net.IP flag.
-func (sc *Subcommand) IP(assignmentVar *net.IP, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) IP(assignmentVar *net.IP, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // IPSlice adds a new int8 slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) IPSlice(assignmentVar *[]net.IP, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) IPSlice(assignmentVar *[]net.IP, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // HardwareAddr adds a new net.HardwareAddr flag.
-func (sc *Subcommand) HardwareAddr(assignmentVar *net.HardwareAddr, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) HardwareAddr(assignmentVar *net.HardwareAddr, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // HardwareAddrSlice adds a new net.HardwareAddr slice flag.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) HardwareAddrSlice(assignmentVar *[]net.HardwareAddr, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) HardwareAddrSlice(assignmentVar *[]net.HardwareAddr, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // IPMask adds a new net.IPMask flag. IPv4 Only.
-func (sc *Subcommand) IPMask(assignmentVar *net.IPMask, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) IPMask(assignmentVar *net.IPMask, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // IPMaskSlice adds a new net.HardwareAddr slice flag. IPv4 only.
 // Specify the flag multiple times to fill the slice.
-func (sc *Subcommand) IPMaskSlice(assignmentVar *[]net.IPMask, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) IPMaskSlice(assignmentVar *[]net.IPMask, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Time adds a new time.Time flag. Supports RFC3339/RFC3339Nano, RFC1123, and unix seconds.
-func (sc *Subcommand) Time(assignmentVar *time.Time, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Time(assignmentVar *time.Time, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // URL adds a new url.URL flag.
-func (sc *Subcommand) URL(assignmentVar *url.URL, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) URL(assignmentVar *url.URL, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // IPNet adds a new net.IPNet flag parsed from CIDR.
-func (sc *Subcommand) IPNet(assignmentVar *net.IPNet, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) IPNet(assignmentVar *net.IPNet, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // TCPAddr adds a new net.TCPAddr flag parsed from host:port.
-func (sc *Subcommand) TCPAddr(assignmentVar *net.TCPAddr, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) TCPAddr(assignmentVar *net.TCPAddr, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // UDPAddr adds a new net.UDPAddr flag parsed from host:port.
-func (sc *Subcommand) UDPAddr(assignmentVar *net.UDPAddr, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) UDPAddr(assignmentVar *net.UDPAddr, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // FileMode adds a new os.FileMode flag parsed from octal/decimal (base auto-detected).
-func (sc *Subcommand) FileMode(assignmentVar *os.FileMode, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) FileMode(assignmentVar *os.FileMode, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Regexp adds a new regexp.Regexp flag.
-func (sc *Subcommand) Regexp(assignmentVar *regexp.Regexp, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Regexp(assignmentVar *regexp.Regexp, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Location adds a new time.Location flag.
-func (sc *Subcommand) Location(assignmentVar *time.Location, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Location(assignmentVar *time.Location, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Month adds a new time.Month flag.
-func (sc *Subcommand) Month(assignmentVar *time.Month, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Month(assignmentVar *time.Month, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // Weekday adds a new time.Weekday flag.
-func (sc *Subcommand) Weekday(assignmentVar *time.Weekday, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) Weekday(assignmentVar *time.Weekday, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // BigInt adds a new big.Int flag.
-func (sc *Subcommand) BigInt(assignmentVar *big.Int, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) BigInt(assignmentVar *big.Int, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // BigRat adds a new big.Rat flag.
-func (sc *Subcommand) BigRat(assignmentVar *big.Rat, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) BigRat(assignmentVar *big.Rat, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // NetipAddr adds a new netip.Addr flag.
-func (sc *Subcommand) NetipAddr(assignmentVar *netip.Addr, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) NetipAddr(assignmentVar *netip.Addr, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // NetipPrefix adds a new netip.Prefix flag.
-func (sc *Subcommand) NetipPrefix(assignmentVar *netip.Prefix, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) NetipPrefix(assignmentVar *netip.Prefix, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
 }
 
 // NetipAddrPort adds a new netip.AddrPort flag.
-func (sc *Subcommand) NetipAddrPort(assignmentVar *netip.AddrPort, shortName string, longName string, description string) {
-	sc.add(assignmentVar, shortName, longName, description)
+func (sc *Subcommand) NetipAddrPort(assignmentVar *netip.AddrPort, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
+}
+
+// StringToString adds a new map of strings flag.  Specify the flag multiple
+// times or separate pairs with commas to fill the map, like --label env=prod
+// or --set a=1,b=2.
+func (sc *Subcommand) StringToString(assignmentVar *map[string]string, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
+}
+
+// StringMap adds a new map of strings flag.  It is the same as StringToString.
+func (sc *Subcommand) StringMap(assignmentVar *map[string]string, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
+}
+
+// StringToInt adds a new map of strings to ints flag.  Specify the flag
+// multiple times or separate pairs with commas to fill the map.
+func (sc *Subcommand) StringToInt(assignmentVar *map[string]int, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
+}
+
+// Var adds a new flag backed by a custom Value implementation.  Values are
+// parsed with Set and displayed in help output with String.
+func (sc *Subcommand) Var(assignmentVar Value, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
+}
+
+// TextVar adds a new flag backed by a type implementing
+// encoding.TextUnmarshaler.  If the type also implements
+// encoding.TextMarshaler, it is used to display the default in help output.
+func (sc *Subcommand) TextVar(assignmentVar encoding.TextUnmarshaler, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
+}
+
+// FlagValue adds a new flag backed by a type implementing the standard
+// library's flag.Value interface.
+func (sc *Subcommand) FlagValue(assignmentVar flag.Value, shortName string, longName string, description string) *Flag {
+	return sc.add(assignmentVar, shortName, longName, description)
+}
+
+// Count adds a new counter flag that is incremented every time it is used,
+// like -v -v or -vv.  It is set directly when given a value, like
+// --verbose=3.
+func (sc *Subcommand) Count(assignmentVar *int, shortName string, longName string, description string) *Flag {
+	return sc.add((*Counter)(assignmentVar), shortName, longName, description)
+}
+
+// Enum adds a new string flag that only accepts one of the allowed values.
+// Other values are rejected with an error listing the choices, which are
+// also shown in help and offered by shell completion.
+func (sc *Subcommand) Enum(assignmentVar *string, shortName string, longName string, description string, allowed []string) *Flag {
+	return sc.add(&enumValue{value: assignmentVar, allowed: allowed}, shortName, longName, description)
+}
+
+// EnumSlice adds a new slice of strings flag that only accepts the allowed
+// values.  Specify the flag multiple times to fill the slice.
+func (sc *Subcommand) EnumSlice(assignmentVar *[]string, shortName string, longName string, description string, allowed []string) *Flag {
+	return sc.add(&enumSliceValue{values: assignmentVar, allowed: allowed}, shortName, longName, description)
 }
 
 // AddPositionalValue adds a positional value to the subcommand.  the
-// relativePosition starts at 1 and is relative to the subcommand it belongs to
-func (sc *Subcommand) AddPositionalValue(assignmentVar *string, name string, relativePosition int, required bool, description string) {
+// relativePosition starts at 1 and is relative to the subcommand it belongs to.
+// The assignmentVar may be a pointer to any type supported by flags,
+// including custom values.
+func (sc *Subcommand) AddPositionalValue(assignmentVar interface{}, name string, relativePosition int, required bool, description string) {
+
+	// ensure the type can be assigned to
+	if _, handled, _ := valueAsString(assignmentVar); !handled {
+		log.Panicln("Unable to add positional value " + name + " because its type is not supported: " + reflect.TypeOf(assignmentVar).String())
+	}
 
 	// ensure no other positionals are at this depth
 	for _, other := range sc.PositionalFlags {
@@ -758,23 +857,71 @@ func (sc *Subcommand) AddPositionalValue(assignmentVar *string, name string, rel
 		AssignmentVar: assignmentVar,
 		Required:      required,
 		Description:   description,
-		defaultValue:  *assignmentVar,
+		defaultValue:  positionalDefaultValue(assignmentVar),
 	}
 	sc.PositionalFlags = append(sc.PositionalFlags, &newPositionalValue)
 }
 
+// AddPositionalSlice adds a variadic positional value to the subcommand that
+// collects every positional value at and after relativePosition.  Fixed
+// positional values added after relativePosition receive the final values,
+// so "copy SRC... DST" takes every value but the last as a source.  At least
+// min and at most max values are accepted, where a max of 0 means no limit.
+// The assignmentVar must be a pointer to a slice type supported by flags.
+func (sc *Subcommand) AddPositionalSlice(assignmentVar interface{}, name string, relativePosition int, min int, max int, description string) {
+
+	for _, other := range sc.PositionalFlags {
+		if other.variadic {
+			log.Panicln("Unable to add positional slice " + name + " because " + other.Name + " already collects the remaining positional values")
+		}
+	}
+	if t := reflect.TypeOf(assignmentVar); t == nil || t.Kind() != reflect.Pointer || t.Elem().Kind() != reflect.Slice {
+		log.Panicln("Unable to add positional slice " + name + " because it must be assigned to a pointer to a slice")
+	}
+	if max > 0 && max < min {
+		log.Panicln("Unable to add positional slice " + name + " because its max is less than its min")
+	}
+	for _, other := range sc.Subcommands {
+		if other.Position >= relativePosition {
+			log.Panicln("Unable to add positional slice " + name + " because a subcommand, " + other.Name + ", exists at or after position: " + strconv.Itoa(relativePosition))
+		}
+	}
+
+	sc.AddPositionalValue(assignmentVar, name, relativePosition, min > 0, description)
+	pos := sc.PositionalFlags[len(sc.PositionalFlags)-1]
+	pos.Min = min
+	pos.Max = max
+	pos.variadic = true
+}
+
 // SetValueForKey sets the value for the specified key. If setting a bool
 // value, then send "true" or "false" as strings.  The returned bool indicates
-// that a value was set.
+// that a value was set.  Flags set this way report SourceProgrammatic as
+// their Source.
 func (sc *Subcommand) SetValueForKey(key string, value string) (bool, error) {
+	return sc.setValueForKey(key, value, SourceProgrammatic)
+}
+
+// setValueForKey sets the value for the specified key and records where the
+// value came from.
+func (sc *Subcommand) setValueForKey(key string, value string, source ValueSource) (bool, error) {
 
 	// debugPrint("Looking to set key", key, "to value", value)
 	// check for and assign flags that match the key
 	for _, f := range sc.Flags {
 		// debugPrint("Evaluating string flag", f.ShortName, "==", key, "||", f.LongName, "==", key)
-		if f.ShortName == key || f.LongName == key {
+		if f.HasName(key) {
 			// debugPrint("Setting string value for", key, "to", value)
-			if err := f.identifyAndAssignValue(value); err != nil {
+			// rejected values leave the flag as it was
+			restore := saveValue(f.AssignmentVar)
+			previousSource, previousRaw := f.Source, f.rawValue
+			err := f.identifyAndAssignValue(value, source)
+			if err == nil {
+				err = runValidate(f.Validate, key, value, -1, false, f.AssignmentVar)
+			}
+			if err != nil {
+				restore()
+				f.Source, f.rawValue = previousSource, previousRaw
 				return false, err
 			}
 			return true, nil
@@ -786,57 +933,31 @@ func (sc *Subcommand) SetValueForKey(key string, value string) (bool, error) {
 }
 
 // ensureNoConflictWithBuiltinHelp ensures that the flags on this subcommand do
-// not conflict with the builtin help flags (-h or --help). Exits the program
-// if a conflict is found.
-func (sc *Subcommand) ensureNoConflictWithBuiltinHelp() {
+// not conflict with the builtin help flags (-h or --help). Returns a
+// *BuiltinFlagConflictError if a conflict is found.
+func (sc *Subcommand) ensureNoConflictWithBuiltinHelp() error {
 	for _, f := range sc.Flags {
-		if f.LongName == helpFlagLongName {
-			sc.exitBecauseOfHelpFlagConflict(f.LongName)
-		}
-		if f.LongName == helpFlagShortName {
-			sc.exitBecauseOfHelpFlagConflict(f.LongName)
+		if f.LongName == helpFlagLongName || f.LongName == helpFlagShortName {
+			return &BuiltinFlagConflictError{Flag: f.LongName}
 		}
-		if f.ShortName == helpFlagLongName {
-			sc.exitBecauseOfHelpFlagConflict(f.ShortName)
-		}
-		if f.ShortName == helpFlagShortName {
-			sc.exitBecauseOfHelpFlagConflict(f.ShortName)
+		if f.ShortName == helpFlagLongName || f.ShortName == helpFlagShortName {
+			return &BuiltinFlagConflictError{Flag: f.ShortName}
 		}
 	}
+	return nil
 }
 
 // ensureNoConflictWithBuiltinVersion ensures that the flags on this subcommand do
-// not conflict with the builtin version flag (--version). Exits the program
-// if a conflict is found.
-func (sc *Subcommand) ensureNoConflictWithBuiltinVersion() {
+// not conflict with the builtin version flag (--version). Returns a
+// *BuiltinFlagConflictError if a conflict is found.
+func (sc *Subcommand) ensureNoConflictWithBuiltinVersion() error {
 	for _, f := range sc.Flags {
 		if f.LongName == versionFlagLongName {
-			sc.exitBecauseOfVersionFlagConflict(f.LongName)
+			return &BuiltinFlagConflictError{Flag: f.LongName, Version: true}
 		}
 		if f.ShortName == versionFlagLongName {
-			sc.exitBecauseOfVersionFlagConflict(f.ShortName)
+			return &BuiltinFlagConflictError{Flag: f.ShortName, Version: true}
 		}
 	}
-}
-
-// exitBecauseOfVersionFlagConflict exits the program with a message about how to prevent
-// flags being defined from conflicting with the builtin flags.
-func (sc *Subcommand) exitBecauseOfVersionFlagConflict(flagName string) {
-	fmt.Println(`Flag with name '` + flagName + `' conflicts with the internal --version flag in flaggy.
-
-You must either change the flag's name, or disable flaggy's internal version
-flag with 'flaggy.DefaultParser.ShowVersionWithVersionFlag = false'.  If you are using
-a custom parser, you must instead set '.ShowVersionWithVersionFlag = false' on it.`)
-	exitOrPanic(1)
-}
-
-// exitBecauseOfHelpFlagConflict exits the program with a message about how to prevent
-// flags being defined from conflicting with the builtin flags.
-func (sc *Subcommand) exitBecauseOfHelpFlagConflict(flagName string) {
-	fmt.Println(`Flag with name '` + flagName + `' conflicts with the internal --help or -h flag in flaggy.
-
-You must either change the flag's name, or disable flaggy's internal help
-flag with 'flaggy.DefaultParser.ShowHelpWithHFlag = false'.  If you are using
-a custom parser, you must instead set '.ShowHelpWithHFlag = false' on it.`)
-	exitOrPanic(1)
+	return nil
 }
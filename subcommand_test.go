@@ -106,7 +106,6 @@ func TestNextArgDoesNotExist(t *testing.T) {
 		}
 	}()
 	flaggy.ResetParser()
-	flaggy.PanicInsteadOfExit = true
 	var test string
 	flaggy.String(&test, "t", "test", "Description goes here")
 	flaggy.ParseArgs([]string{"-t"})
@@ -288,9 +287,6 @@ func TestMultipleRootSubcommandsAcrossPositions(t *testing.T) {
 }
 
 func TestMultipleRootSubcommandHelpVariants(t *testing.T) {
-	flaggy.PanicInsteadOfExit = true
-	defer func() { flaggy.PanicInsteadOfExit = false }()
-
 	counts := []int{2, 3, 4}
 	flags := []string{"-h", "--help", "-help", "--h"}
 
@@ -321,9 +317,6 @@ func TestMultipleRootSubcommandHelpVariants(t *testing.T) {
 }
 
 func TestNestedSubcommandDispatchAndHelp(t *testing.T) {
-	flaggy.PanicInsteadOfExit = true
-	defer func() { flaggy.PanicInsteadOfExit = false }()
-
 	rootCounts := []int{2, 3, 4}
 	childCount := 3
 	flags := []string{"-h", "--help", "-help", "--h"}
@@ -432,9 +425,6 @@ func TestVersionWithVFlagB(t *testing.T) {
 }
 
 func TestSubcommandRespectsPositionalOrdering(t *testing.T) {
-	flaggy.PanicInsteadOfExit = true
-	defer func() { flaggy.PanicInsteadOfExit = false }()
-
 	parser := flaggy.NewParser("deployctl")
 	var environment string
 	parser.AddPositionalValue(&environment, "environment", 1, true, "")
@@ -1048,9 +1038,6 @@ func TestParseErrorsAreReportedRegression(t *testing.T) {
 // the flag should take precedence for a dash-prefixed token and not cause an error.
 func TestSubcommandAndFlagSameShortName(t *testing.T) {
 	flaggy.ResetParser()
-	flaggy.PanicInsteadOfExit = true
-	defer func() { flaggy.PanicInsteadOfExit = false }()
-
 	var test string
 	sc := flaggy.NewSubcommand("testSubCmd")
 	sc.ShortName = "t"
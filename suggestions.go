@@ -0,0 +1,135 @@
+package flaggy
+
+import (
+	"sort"
+	"strings"
+)
+
+// defaultSuggestionDistance is the edit distance used for suggestions by
+// parsers created with NewParser.
+const defaultSuggestionDistance = 2
+
+// levenshtein returns the number of single character insertions, deletions,
+// and substitutions needed to turn a into b.
+func levenshtein(a string, b string) int {
+	ar := []rune(a)
+	br := []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(br)]
+}
+
+// suggest returns the candidates closest to the input, as long as they are
+// within maxDistance edits.  Candidates must also be longer than their
+// distance from the input so that short names are not suggested for
+// unrelated input.  All candidates tied for the closest distance are
+// returned in sorted order.
+func suggest(input string, candidates []string, maxDistance int) []string {
+	if maxDistance <= 0 || input == "" {
+		return nil
+	}
+	best := maxDistance + 1
+	var matches []string
+	for _, c := range candidates {
+		if c == "" {
+			continue
+		}
+		d := levenshtein(strings.ToLower(input), strings.ToLower(c))
+		if d > maxDistance || d >= len([]rune(c)) || d > best {
+			continue
+		}
+		if d < best {
+			best = d
+			matches = nil
+		}
+		matches = append(matches, c)
+	}
+	sort.Strings(matches)
+	return dedupeStrings(matches)
+}
+
+// dedupeStrings removes repeated values from a sorted slice.
+func dedupeStrings(values []string) []string {
+	var out []string
+	for i, v := range values {
+		if i > 0 && values[i-1] == v {
+			continue
+		}
+		out = append(out, v)
+	}
+	return out
+}
+
+// suggestSubcommands finds the visible subcommands at the supplied position
+// whose name, short name, or an alias is close to the input.
+func (p *Parser) suggestSubcommands(sc *Subcommand, input string, position int) []string {
+	var candidates []string
+	for _, cmd := range sc.Subcommands {
+		if cmd.hidden() || cmd.Position != position {
+			continue
+		}
+		candidates = append(candidates, cmd.Name, cmd.ShortName)
+		candidates = append(candidates, cmd.Aliases...)
+	}
+	return suggest(input, candidates, p.SuggestionDistance)
+}
+
+// suggestFlags finds the visible flags on the current subcommand and the
+// root parser whose name or an alias is close to the unknown argument.
+// Suggestions are returned as they would be typed on the command line.
+func (p *Parser) suggestFlags(arg string) []string {
+	name, _ := parseArgWithValue(arg)
+	flags := p.Flags
+	if p.subcommandContext != nil && p.subcommandContext != &p.Subcommand {
+		flags = append(append([]*Flag{}, p.subcommandContext.Flags...), flags...)
+	}
+	byName := make(map[string]string)
+	var candidates []string
+	for _, f := range flags {
+		if f.hidden() {
+			continue
+		}
+		if f.LongName != "" {
+			byName[f.LongName] = "--" + f.LongName
+			candidates = append(candidates, f.LongName)
+		}
+		if f.ShortName != "" {
+			byName[f.ShortName] = "-" + f.ShortName
+			candidates = append(candidates, f.ShortName)
+		}
+		for _, alias := range f.Aliases {
+			byName[alias] = "--" + alias
+			candidates = append(candidates, alias)
+		}
+	}
+	var suggestions []string
+	for _, match := range suggest(name, candidates, p.SuggestionDistance) {
+		suggestions = append(suggestions, byName[match])
+	}
+	return suggestions
+}
+
+// formatSuggestions renders suggestions as a "Did you mean" question.
+func formatSuggestions(suggestions []string) string {
+	return "Did you mean '" + strings.Join(suggestions, "' or '") + "'?"
+}
+
+// SetSuggestionDistance sets the maximum edit distance used when suggesting
+// subcommands and flags on the default parser.  Zero disables suggestions.
+func SetSuggestionDistance(distance int) {
+	DefaultParser.SuggestionDistance = distance
+}
@@ -0,0 +1,122 @@
+package flaggy
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"deploy", "deploy", 0},
+		{"depoly", "deploy", 2},
+		{"deplo", "deploy", 1},
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	candidates := []string{"deploy", "destroy", "d", "status"}
+	if got := suggest("deplyo", candidates, 2); strings.Join(got, ",") != "deploy" {
+		t.Fatalf("expected deploy suggestion, got %v", got)
+	}
+	if got := suggest("x", candidates, 2); len(got) != 0 {
+		t.Fatalf("expected short names to be ignored for unrelated input, got %v", got)
+	}
+	if got := suggest("deplyo", candidates, 0); len(got) != 0 {
+		t.Fatalf("expected a zero distance to disable suggestions, got %v", got)
+	}
+}
+
+// parseUnknown parses the args and returns the resulting unknown argument
+// error.
+func parseUnknown(t *testing.T, p *Parser, args []string) *UnknownArgumentError {
+	t.Helper()
+	p.ErrorHandling = ContinueOnError
+	err := p.ParseArgs(args)
+	var unknown *UnknownArgumentError
+	if !errors.As(err, &unknown) {
+		t.Fatalf("expected *UnknownArgumentError, got %T: %v", err, err)
+	}
+	return unknown
+}
+
+func TestSuggestSubcommand(t *testing.T) {
+	p := NewParser("app")
+	deploy := NewSubcommand("deploy")
+	deploy.ShortName = "dep"
+	p.AttachSubcommand(deploy, 1)
+	secret := NewSubcommand("deplete")
+	secret.Hidden = true
+	p.AttachSubcommand(secret, 1)
+
+	unknown := parseUnknown(t, p, []string{"deplot"})
+	if got := unknown.Suggestions["deplot"]; strings.Join(got, ",") != "deploy" {
+		t.Fatalf("expected deploy to be suggested, got %v", got)
+	}
+	if !strings.Contains(unknown.Error(), "Did you mean 'deploy'?") {
+		t.Fatalf("expected suggestion in error message, got %q", unknown.Error())
+	}
+}
+
+func TestSuggestFlag(t *testing.T) {
+	p := NewParser("app")
+	var verbose, hidden bool
+	p.Bool(&verbose, "v", "verbose", "verbose output")
+	p.Bool(&hidden, "", "verbatim", "hidden flag").Hidden = true
+	sub := NewSubcommand("deploy")
+	var region string
+	sub.String(&region, "r", "region", "region")
+	p.AttachSubcommand(sub, 1)
+
+	unknown := parseUnknown(t, p, []string{"deploy", "--verbos", "--regoin=x"})
+	if got := unknown.Suggestions["verbos"]; strings.Join(got, ",") != "--verbose" {
+		t.Fatalf("expected --verbose to be suggested, got %v", got)
+	}
+	if got := unknown.Suggestions["regoin=x"]; strings.Join(got, ",") != "--region" {
+		t.Fatalf("expected --region to be suggested from the subcommand, got %v", got)
+	}
+	if !strings.Contains(unknown.Error(), "Did you mean '--verbose' instead of 'verbos'?") {
+		t.Fatalf("expected suggestion in error message, got %q", unknown.Error())
+	}
+}
+
+func TestSuggestAliases(t *testing.T) {
+	p := NewParser("app")
+	run := NewSubcommand("run")
+	run.Aliases = []string{"start"}
+	p.AttachSubcommand(run, 1)
+	unknown := parseUnknown(t, p, []string{"strat"})
+	if got := unknown.Suggestions["strat"]; strings.Join(got, ",") != "start" {
+		t.Fatalf("expected the start alias to be suggested, got %v", got)
+	}
+
+	p = NewParser("app")
+	var region string
+	p.String(&region, "r", "region", "region").Aliases = []string{"zone"}
+	unknown = parseUnknown(t, p, []string{"--zoen", "x"})
+	if got := unknown.Suggestions["zoen"]; strings.Join(got, ",") != "--zone" {
+		t.Fatalf("expected the --zone alias to be suggested, got %v", got)
+	}
+}
+
+func TestSuggestionDistanceDisabled(t *testing.T) {
+	p := NewParser("app")
+	p.SuggestionDistance = 0
+	var verbose bool
+	p.Bool(&verbose, "v", "verbose", "verbose output")
+	unknown := parseUnknown(t, p, []string{"--verbos"})
+	if len(unknown.Suggestions) != 0 {
+		t.Fatalf("expected no suggestions, got %v", unknown.Suggestions)
+	}
+}
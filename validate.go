@@ -0,0 +1,240 @@
+package flaggy
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+)
+
+// ValidationError is returned when a value was converted but rejected by
+// the Validate function of its flag or positional value.
+type ValidationError struct {
+	Flag       string // the name of the flag, as supplied, or the name of the positional value
+	Value      string // the raw value that was rejected
+	Position   int    // the index of the flag in the parsed arguments, the relative position of a positional value, or -1 when not from the command line
+	Positional bool   // indicates the value was supplied for a positional value
+	Err        error  // the error returned by the Validate function
+}
+
+func (e *ValidationError) Error() string {
+	if e.Positional {
+		return "Invalid value '" + e.Value + "' for positional value " + e.Flag + " at position " + strconv.Itoa(e.Position) + ": " + e.Err.Error()
+	}
+	return "Invalid value '" + e.Value + "' for flag " + e.Flag + ": " + e.Err.Error()
+}
+
+// Unwrap returns the error returned by the Validate function.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// valueError describes an error from assigning a value to a flag.
+// Validation errors already name the flag and are returned as they are,
+// while anything else is wrapped in a ConversionError.
+func valueError(key string, value string, position int, err error) error {
+	var validation *ValidationError
+	if errors.As(err, &validation) {
+		validation.Position = position
+		return validation
+	}
+	return &ConversionError{Flag: key, Value: value, Position: position, Err: err}
+}
+
+// currentValue returns the value held by an assignment variable for
+// validation.  Pointers are dereferenced, and the values wrapped by enums
+// are returned in place of the enum.
+func currentValue(assignmentVar interface{}) interface{} {
+	switch v := assignmentVar.(type) {
+	case *enumValue:
+		return *v.value
+	case *enumSliceValue:
+		return *v.values
+	case *Counter:
+		return int(*v)
+	}
+	rv := reflect.ValueOf(assignmentVar)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		return rv.Elem().Interface()
+	}
+	return assignmentVar
+}
+
+// saveValue remembers the value held by an assignment variable and returns
+// a function that puts it back, so that rejected values can be undone.  Maps
+// are copied because assigning to them changes them in place.
+func saveValue(assignmentVar interface{}) (restore func()) {
+	switch v := assignmentVar.(type) {
+	case *enumValue:
+		saved := *v.value
+		return func() { *v.value = saved }
+	case *enumSliceValue:
+		saved := *v.values
+		return func() { *v.values = saved }
+	}
+	rv := reflect.ValueOf(assignmentVar)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return func() {}
+	}
+	elem := rv.Elem()
+	saved := reflect.New(elem.Type()).Elem()
+	saved.Set(elem)
+	if elem.Kind() == reflect.Map && !elem.IsNil() {
+		saved.Set(reflect.MakeMapWithSize(elem.Type(), elem.Len()))
+		iter := elem.MapRange()
+		for iter.Next() {
+			saved.SetMapIndex(iter.Key(), iter.Value())
+		}
+	}
+	return func() { elem.Set(saved) }
+}
+
+// runValidate calls the validate function with the current value of the
+// assignment variable, if there is one.
+func runValidate(validate func(value interface{}) error, name string, raw string, position int, positional bool, assignmentVar interface{}) error {
+	if validate == nil {
+		return nil
+	}
+	if err := validate(currentValue(assignmentVar)); err != nil {
+		return &ValidationError{Flag: name, Value: raw, Position: position, Positional: positional, Err: err}
+	}
+	return nil
+}
+
+// eachElement calls fn with every element of an unnamed slice, or with the
+// value itself when it is not one, stopping at the first error.
+func eachElement(value interface{}, fn func(reflect.Value) error) error {
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice || rv.Type().Name() != "" {
+		return fn(rv)
+	}
+	for i := 0; i < rv.Len(); i++ {
+		if err := fn(rv.Index(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// numericValue converts integer and floating point values for range checks.
+func numericValue(v reflect.Value) (float64, error) {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	}
+	return 0, errors.New("value of type " + v.Type().String() + " is not numeric")
+}
+
+// formatBound renders a range limit without needless decimals.
+func formatBound(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// ValidateMin returns a Validate function that rejects numbers, or the
+// elements of numeric slices, that are less than min.
+func ValidateMin(min float64) func(value interface{}) error {
+	return func(value interface{}) error {
+		return eachElement(value, func(v reflect.Value) error {
+			n, err := numericValue(v)
+			if err != nil {
+				return err
+			}
+			if n < min {
+				return errors.New("must be at least " + formatBound(min))
+			}
+			return nil
+		})
+	}
+}
+
+// ValidateMax returns a Validate function that rejects numbers, or the
+// elements of numeric slices, that are greater than max.
+func ValidateMax(max float64) func(value interface{}) error {
+	return func(value interface{}) error {
+		return eachElement(value, func(v reflect.Value) error {
+			n, err := numericValue(v)
+			if err != nil {
+				return err
+			}
+			if n > max {
+				return errors.New("must be at most " + formatBound(max))
+			}
+			return nil
+		})
+	}
+}
+
+// ValidateRange returns a Validate function that rejects numbers, or the
+// elements of numeric slices, outside of min and max inclusive.
+func ValidateRange(min float64, max float64) func(value interface{}) error {
+	return func(value interface{}) error {
+		return eachElement(value, func(v reflect.Value) error {
+			n, err := numericValue(v)
+			if err != nil {
+				return err
+			}
+			if n < min || n > max {
+				return errors.New("must be between " + formatBound(min) + " and " + formatBound(max))
+			}
+			return nil
+		})
+	}
+}
+
+// ValidateMatch returns a Validate function that rejects values, or the
+// elements of slices, that do not match the regular expression.  Values
+// that are not strings are matched in their printed form.  An invalid
+// pattern panics.
+func ValidateMatch(pattern string) func(value interface{}) error {
+	re := regexp.MustCompile(pattern)
+	return func(value interface{}) error {
+		return eachElement(value, func(v reflect.Value) error {
+			if !re.MatchString(fmt.Sprint(v.Interface())) {
+				return errors.New("must match " + pattern)
+			}
+			return nil
+		})
+	}
+}
+
+// ValidateFileExists returns a Validate function that rejects paths, or the
+// elements of slices of paths, that do not exist.
+func ValidateFileExists() func(value interface{}) error {
+	return func(value interface{}) error {
+		return eachElement(value, func(v reflect.Value) error {
+			path := fmt.Sprint(v.Interface())
+			if _, err := os.Stat(path); err != nil {
+				if errors.Is(err, os.ErrNotExist) {
+					return errors.New("file " + path + " does not exist")
+				}
+				return err
+			}
+			return nil
+		})
+	}
+}
+
+// ValidateNotEmpty returns a Validate function that rejects zero values,
+// like empty strings, and slices or maps without any elements.
+func ValidateNotEmpty() func(value interface{}) error {
+	return func(value interface{}) error {
+		rv := reflect.ValueOf(value)
+		switch rv.Kind() {
+		case reflect.Slice, reflect.Map:
+			if rv.Len() == 0 {
+				return errors.New("must not be empty")
+			}
+		default:
+			if !rv.IsValid() || rv.IsZero() {
+				return errors.New("must not be empty")
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,179 @@
+package flaggy_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+func TestValidate(t *testing.T) {
+	existing := filepath.Join(t.TempDir(), "config.json")
+	if err := os.WriteFile(existing, []byte("{}"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	missing := filepath.Join(t.TempDir(), "missing.json")
+
+	tests := []struct {
+		name     string
+		validate func(value interface{}) error
+		value    interface{}
+		args     []string
+		ok       bool
+	}{
+		{name: "port in range", validate: flaggy.ValidateRange(1, 65535), value: new(int), args: []string{"--value", "8080"}, ok: true},
+		{name: "port out of range", validate: flaggy.ValidateRange(1, 65535), value: new(int), args: []string{"--value", "99999"}},
+		{name: "ratio below min", validate: flaggy.ValidateMin(0), value: new(float64), args: []string{"--value=-3"}},
+		{name: "ratio above max", validate: flaggy.ValidateMax(1), value: new(float64), args: []string{"--value", "1.5"}},
+		{name: "slice element out of range", validate: flaggy.ValidateMax(10), value: new([]int), args: []string{"--value", "1", "--value", "11"}},
+		{name: "match", validate: flaggy.ValidateMatch(`^[a-z]+$`), value: new(string), args: []string{"--value", "abc"}, ok: true},
+		{name: "no match", validate: flaggy.ValidateMatch(`^[a-z]+$`), value: new(string), args: []string{"--value", "ABC"}},
+		{name: "file exists", validate: flaggy.ValidateFileExists(), value: new(string), args: []string{"--value", existing}, ok: true},
+		{name: "file missing", validate: flaggy.ValidateFileExists(), value: new(string), args: []string{"--value", missing}},
+		{name: "not empty", validate: flaggy.ValidateNotEmpty(), value: new(string), args: []string{"--value", "x"}, ok: true},
+		{name: "empty", validate: flaggy.ValidateNotEmpty(), value: new(string), args: []string{"--value="}},
+		{name: "not numeric", validate: flaggy.ValidateMin(1), value: new(string), args: []string{"--value", "1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := flaggy.NewParser("app")
+			p.ErrorHandling = flaggy.ContinueOnError
+			var f *flaggy.Flag
+			switch v := tt.value.(type) {
+			case *int:
+				f = p.Int(v, "", "value", "an int")
+			case *float64:
+				f = p.Float64(v, "", "value", "a float")
+			case *[]int:
+				f = p.IntSlice(v, "", "value", "some ints")
+			case *string:
+				f = p.String(v, "", "value", "a string")
+			}
+			f.Validate = tt.validate
+			err := p.ParseArgs(tt.args)
+			if tt.ok {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			var validation *flaggy.ValidationError
+			if !errors.As(err, &validation) {
+				t.Fatalf("expected *ValidationError, got %T: %v", err, err)
+			}
+			if validation.Flag != "value" || validation.Position < 0 {
+				t.Fatalf("expected the error to name the flag and its position, got %+v", validation)
+			}
+			if flaggy.ExitCode(err) != 2 {
+				t.Fatalf("expected exit code 2, got %d", flaggy.ExitCode(err))
+			}
+		})
+	}
+}
+
+func TestValidateLeavesRejectedValuesUnassigned(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	port := 8080
+	f := p.Int(&port, "p", "port", "Port")
+	f.Validate = flaggy.ValidateRange(1, 65535)
+	var validation *flaggy.ValidationError
+	if err := p.ParseArgs([]string{"--port", "99999"}); !errors.As(err, &validation) {
+		t.Fatalf("expected *ValidationError, got %T: %v", err, err)
+	}
+	if port != 8080 || f.Changed() {
+		t.Fatalf("expected the rejected port to be left out, got %d from %v", port, f.Source)
+	}
+
+	p = flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	ports := []int{1}
+	p.IntSlice(&ports, "", "ports", "Ports").Validate = flaggy.ValidateMax(10)
+	if err := p.ParseArgs([]string{"--ports", "2", "--ports", "11"}); !errors.As(err, &validation) {
+		t.Fatalf("expected *ValidationError, got %T: %v", err, err)
+	}
+	if len(ports) != 2 || ports[1] != 2 {
+		t.Fatalf("expected only the rejected element to be left out, got %v", ports)
+	}
+
+	p = flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	labels := map[string]string{"team": "web"}
+	p.StringMap(&labels, "l", "label", "Labels").Validate = func(value interface{}) error {
+		if _, ok := value.(map[string]string)["bad"]; ok {
+			return errors.New("bad label")
+		}
+		return nil
+	}
+	if err := p.ParseArgs([]string{"--label", "bad=1"}); !errors.As(err, &validation) {
+		t.Fatalf("expected *ValidationError, got %T: %v", err, err)
+	}
+	if len(labels) != 1 || labels["team"] != "web" {
+		t.Fatalf("expected the rejected label to be left out, got %v", labels)
+	}
+
+	p = flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	count := 3
+	p.AddPositionalValue(&count, "count", 1, true, "How many")
+	p.PositionalFlags[0].Validate = flaggy.ValidateMin(1)
+	if err := p.ParseArgs([]string{"0"}); !errors.As(err, &validation) {
+		t.Fatalf("expected *ValidationError, got %T: %v", err, err)
+	}
+	if count != 3 {
+		t.Fatalf("expected the rejected positional value to be left out, got %d", count)
+	}
+}
+
+func TestValidateFromEnvironment(t *testing.T) {
+	t.Setenv("APP_PORT", "0")
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var port int
+	f := p.Int(&port, "p", "port", "Port")
+	f.EnvVar = "APP_PORT"
+	f.Validate = flaggy.ValidateRange(1, 65535)
+	err := p.ParseArgs([]string{})
+	var validation *flaggy.ValidationError
+	if !errors.As(err, &validation) || validation.Position != -1 {
+		t.Fatalf("expected *ValidationError from the environment, got %T: %v", err, err)
+	}
+}
+
+func TestValidatePositional(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var count int
+	p.AddPositionalValue(&count, "count", 1, true, "How many")
+	p.PositionalFlags[0].Validate = flaggy.ValidateMin(1)
+	err := p.ParseArgs([]string{"0"})
+	var validation *flaggy.ValidationError
+	if !errors.As(err, &validation) || !validation.Positional || validation.Position != 1 {
+		t.Fatalf("expected a positional *ValidationError, got %T: %v", err, err)
+	}
+	if !strings.Contains(err.Error(), "count at position 1: must be at least 1") {
+		t.Fatalf("unexpected message: %v", err)
+	}
+}
+
+func TestConstraintInHelp(t *testing.T) {
+	p := flaggy.NewParser("app")
+	p.ErrorHandling = flaggy.ContinueOnError
+	var port, count int
+	f := p.Int(&port, "p", "port", "Port to listen on")
+	f.Validate = flaggy.ValidateRange(1, 65535)
+	f.Constraint = "between 1 and 65535"
+	p.AddPositionalValue(&count, "count", 1, false, "How many")
+	p.PositionalFlags[0].Constraint = "at least 1"
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	out := strings.Join(h.Lines, "\n")
+	for _, want := range []string{"(between 1 and 65535)", "How many (at least 1)"} {
+		if !strings.Contains(out, want) {
+			t.Fatalf("expected help to contain %q:\n%s", want, out)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package flaggy
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+// Value is the interface implemented by custom flag types.  Any type that
+// satisfies Value can be registered with Var and will be parsed and
+// rendered in help output using its own methods.
+type Value interface {
+	Set(string) error // parses the supplied string and stores the result
+	String() string   // returns the current value for help output
+	Type() string     // returns a short name for the type, like "semver"
+}
+
+// stdValue matches the standard library's flag.Value interface.  Types that
+// implement it are accepted even when they do not provide a Type method.
+type stdValue interface {
+	Set(string) error
+	String() string
+}
+
+// boolValue is implemented by custom values that do not need a following
+// argument, matching the IsBoolFlag convention of the standard library.
+type boolValue interface {
+	IsBoolFlag() bool
+}
+
+// isBoolValue reports whether the supplied assignment variable is a custom
+// value that asks to be used without a following argument.
+func isBoolValue(assignmentVar interface{}) bool {
+	bv, ok := assignmentVar.(boolValue)
+	return ok && bv.IsBoolFlag()
+}
+
+// assignCustomValue sets a custom value from its string form.  The returned
+// bool is false when the assignment variable is not a supported custom type.
+func assignCustomValue(assignmentVar interface{}, value string) (bool, error) {
+	switch v := assignmentVar.(type) {
+	case Value:
+		return true, v.Set(value)
+	case stdValue:
+		return true, v.Set(value)
+	case encoding.TextUnmarshaler:
+		return true, v.UnmarshalText([]byte(value))
+	}
+	return false, nil
+}
+
+// customValueAsString returns the string form of a custom value.  The
+// returned bool is false when the assignment variable is not a supported
+// custom type.
+func customValueAsString(assignmentVar interface{}) (string, bool, error) {
+	switch v := assignmentVar.(type) {
+	case Value:
+		return v.String(), true, nil
+	case stdValue:
+		return v.String(), true, nil
+	case encoding.TextMarshaler:
+		text, err := v.MarshalText()
+		return string(text), true, err
+	case encoding.TextUnmarshaler:
+		rv := reflect.ValueOf(v)
+		if rv.Kind() == reflect.Ptr {
+			if rv.IsNil() {
+				return "", true, nil
+			}
+			return fmt.Sprint(rv.Elem().Interface()), true, nil
+		}
+		return fmt.Sprint(v), true, nil
+	}
+	return "", false, nil
+}
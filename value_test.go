@@ -0,0 +1,144 @@
+package flaggy_test
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/integrii/flaggy"
+)
+
+// logLevel is a custom Value implementation used to exercise Var.
+type logLevel int
+
+func (l *logLevel) Set(s string) error {
+	switch strings.ToLower(s) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	default:
+		return errors.New("unknown log level " + s)
+	}
+	return nil
+}
+
+func (l *logLevel) String() string {
+	return [...]string{"debug", "info", "error"}[*l]
+}
+
+func (l *logLevel) Type() string {
+	return "level"
+}
+
+// semver is a TextUnmarshaler/TextMarshaler pair used to exercise TextVar.
+type semver struct {
+	Major, Minor, Patch int
+}
+
+func (v *semver) UnmarshalText(text []byte) error {
+	parts := strings.Split(strings.TrimPrefix(string(text), "v"), ".")
+	if len(parts) != 3 {
+		return errors.New("invalid semver " + string(text))
+	}
+	nums := make([]int, 3)
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil {
+			return err
+		}
+		nums[i] = n
+	}
+	v.Major, v.Minor, v.Patch = nums[0], nums[1], nums[2]
+	return nil
+}
+
+func (v semver) MarshalText() ([]byte, error) {
+	return []byte("v" + strconv.Itoa(v.Major) + "." + strconv.Itoa(v.Minor) + "." + strconv.Itoa(v.Patch)), nil
+}
+
+// toggle is a flag.Value that reports IsBoolFlag so it needs no argument.
+type toggle struct {
+	on bool
+}
+
+func (t *toggle) Set(s string) error {
+	v, err := strconv.ParseBool(s)
+	t.on = v
+	return err
+}
+
+func (t *toggle) String() string   { return strconv.FormatBool(t.on) }
+func (t *toggle) IsBoolFlag() bool { return true }
+
+func TestVarCustomValue(t *testing.T) {
+	p := flaggy.NewParser("TestVarCustomValue")
+	level := logLevel(1)
+	p.Var(&level, "l", "level", "log level")
+	if err := p.ParseArgs([]string{"--level", "error"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if level != 2 {
+		t.Fatalf("expected level error (2), got %d", level)
+	}
+}
+
+func TestVarCustomValueError(t *testing.T) {
+	p := flaggy.NewParser("TestVarCustomValueError")
+	var level logLevel
+	p.Var(&level, "l", "level", "log level")
+	if err := p.ParseArgs([]string{"--level", "loud"}); err == nil {
+		t.Fatal("expected error for invalid custom value")
+	}
+}
+
+func TestTextVar(t *testing.T) {
+	p := flaggy.NewParser("TestTextVar")
+	sub := flaggy.NewSubcommand("release")
+	p.AttachSubcommand(sub, 1)
+	version := semver{Major: 1}
+	sub.TextVar(&version, "", "version-tag", "release version")
+	if err := p.ParseArgs([]string{"release", "--version-tag=v2.3.4"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if version != (semver{2, 3, 4}) {
+		t.Fatalf("unexpected version: %+v", version)
+	}
+}
+
+func TestFlagValueBool(t *testing.T) {
+	p := flaggy.NewParser("TestFlagValueBool")
+	var tog toggle
+	var pos string
+	p.FlagValue(&tog, "t", "toggle", "a boolean flag.Value")
+	p.AddPositionalValue(&pos, "target", 1, false, "target")
+	if err := p.ParseArgs([]string{"-t", "dest"}); err != nil {
+		t.Fatalf("parse error: %v", err)
+	}
+	if !tog.on {
+		t.Fatal("expected toggle to be set without a value")
+	}
+	if pos != "dest" {
+		t.Fatalf("expected positional dest, got %q", pos)
+	}
+}
+
+func TestCustomValueDefaultInHelp(t *testing.T) {
+	p := flaggy.NewParser("TestCustomValueDefaultInHelp")
+	version := semver{Major: 1, Minor: 2}
+	p.TextVar(&version, "", "version-tag", "release version")
+	var h flaggy.Help
+	h.ExtractValues(p, "")
+	for _, f := range h.Flags {
+		if f.LongName == "version-tag" {
+			if f.DefaultValue != "v1.2.0" {
+				t.Fatalf("expected default v1.2.0, got %q", f.DefaultValue)
+			}
+			return
+		}
+	}
+	t.Fatal("version-tag flag missing from help output")
+}